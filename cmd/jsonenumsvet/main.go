@@ -0,0 +1,28 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Jsonenumsvet runs the exhaustive analyzer, reporting switch statements over
+// the types generated for by jsonenums that miss some of their constants. It
+// is meant to be run by go vet:
+//
+//	go vet -vettool=$(which jsonenumsvet) ./...
+package main
+
+import (
+	"github.com/davars/jsonenums/exhaustive"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(exhaustive.Analyzer)
+}
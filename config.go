@@ -0,0 +1,284 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/davars/jsonenums/generator"
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is the name of the configuration file read from the directory of
+// the package.
+const configFile = ".jsonenums.yaml"
+
+// A config holds the settings read from a configuration file.
+type config struct {
+	// Flags holds the values of flags, by name. Lists are joined with commas.
+	Flags map[string]interface{} `yaml:",inline"`
+	// Templates are executed in addition to the built-in template or the one
+	// named by the template flag.
+	Templates []struct {
+		File   string `yaml:"file"`   // The text/template file.
+		Output string `yaml:"output"` // The generated file, relative to the package.
+	} `yaml:"templates"`
+}
+
+// An extraTemplate is a template executed in addition to the main one, and the
+// name of the file it generates in the directory of each package.
+type extraTemplate struct {
+	tmpl   *template.Template
+	output string
+}
+
+// readConfig reads the configuration file in dir, returning nil if there is
+// none. The values of its flags are turned into strings, with the files they
+// name made relative to the current directory.
+func readConfig(dir string) (*config, error) {
+	path := filepath.Join(dir, configFile)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+	for name, value := range cfg.Flags {
+		if flag.Lookup(name) == nil {
+			return nil, fmt.Errorf("%s: unknown flag %q", path, name)
+		}
+		s := fmt.Sprint(value)
+		if list, ok := value.([]interface{}); ok {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			s = strings.Join(items, ",")
+		}
+		if name == "template" || name == "openapi" || name == "header" || name == "displaynames" {
+			// Files are named relative to the configuration file.
+			s = filepath.Join(dir, s)
+		}
+		cfg.Flags[name] = s
+	}
+	return &cfg, nil
+}
+
+// templates parses the extra templates listed by cfg, read from dir.
+func (cfg *config) templates(dir string) ([]extraTemplate, error) {
+	var extra []extraTemplate
+	for _, t := range cfg.Templates {
+		if t.File == "" || t.Output == "" {
+			return nil, fmt.Errorf("%s: templates need a file and an output", filepath.Join(dir, configFile))
+		}
+		text, err := ioutil.ReadFile(filepath.Join(dir, t.File))
+		if err != nil {
+			return nil, fmt.Errorf("reading template: %v", err)
+		}
+		tmpl, err := generator.ParseTemplate(filepath.Base(t.File), string(text), nil)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template: %v", err)
+		}
+		extra = append(extra, extraTemplate{tmpl: tmpl, output: t.Output})
+	}
+	return extra, nil
+}
+
+var (
+	// commandLineFlags holds the names of the flags given on the command
+	// line, which take precedence over the configuration files.
+	commandLineFlags map[string]bool
+	// runConfigDir is the absolute path of the directory of the
+	// configuration file applying to the whole run.
+	runConfigDir string
+)
+
+// loadConfig reads the configuration file in dir, if there is one, and sets
+// the flags it holds that are not given on the command line. It returns the
+// extra templates it lists.
+func loadConfig(dir string) ([]extraTemplate, error) {
+	commandLineFlags = make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { commandLineFlags[f.Name] = true })
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	runConfigDir = abs
+	cfg, err := readConfig(dir)
+	if cfg == nil || err != nil {
+		return nil, err
+	}
+	for name, value := range cfg.Flags {
+		if commandLineFlags[name] {
+			continue
+		}
+		if err := flag.Set(name, value.(string)); err != nil {
+			return nil, fmt.Errorf("%s: flag %s: %v", filepath.Join(dir, configFile), name, err)
+		}
+	}
+	return cfg.templates(dir)
+}
+
+// A packageSettings holds the settings of a package: those of the run with
+// the configuration file of the package applied.
+type packageSettings struct {
+	opts   generator.Options
+	layout layout
+	types  []string        // The types listed by the file, if any.
+	extra  []extraTemplate // The extra templates listed by the file.
+	set    map[string]bool // The flags set by the file.
+}
+
+// packageConfig returns the settings of the package in dir, those of opts and
+// l with its configuration file, if any, applied. Unlike the file loadConfig
+// reads, which applies to the whole run, it may only set the flags setting
+// the fields of generator.Options, as the line comments of types do, and type,
+// prefix, suffix, filename and split. Flags given on the command line take
+// precedence.
+func packageConfig(dir string, opts generator.Options, l layout) (packageSettings, error) {
+	settings := packageSettings{opts: opts, layout: l, set: make(map[string]bool)}
+	cfg, err := readConfig(dir)
+	if cfg == nil || err != nil {
+		return settings, err
+	}
+	path := filepath.Join(dir, configFile)
+	for name, value := range cfg.Flags {
+		if commandLineFlags[name] {
+			continue
+		}
+		settings.set[name] = true
+		s := value.(string)
+		switch name {
+		case "type":
+			settings.types = strings.Split(s, ",")
+		case "prefix":
+			settings.layout.prefix = s
+		case "suffix":
+			settings.layout.suffix = s
+		case "filename":
+			settings.layout.filename = s
+		case "split":
+			if settings.layout.split, err = strconv.ParseBool(s); err != nil {
+				return settings, fmt.Errorf("%s: flag split: %v", path, err)
+			}
+		default:
+			if !optionField(&settings.opts, name).IsValid() {
+				return settings, fmt.Errorf("%s: flag %s applies to the whole run and cannot be set for a single package", path, name)
+			}
+			if err := setOption(&settings.opts, name, s); err != nil {
+				return settings, fmt.Errorf("%s: %v", path, err)
+			}
+		}
+	}
+	filename := settings.layout.filename
+	if filename != "" && (filepath.Base(filename) != filename || !strings.HasSuffix(filename, ".go")) {
+		return settings, fmt.Errorf("%s: the flag filename must name a .go file, without a directory", path)
+	}
+	if settings.layout.split && filename != "" {
+		return settings, fmt.Errorf("%s: the flags filename and split cannot be used together", path)
+	}
+	settings.extra, err = cfg.templates(dir)
+	return settings, err
+}
+
+// typeOptionFields maps the flags that may be given in the line comment of a
+// type, as read by parser.Package.TypeOptions, to the fields of
+// generator.Options they set, when their names differ.
+var typeOptionFields = map[string]string{
+	"numbers": "AcceptNumbers",
+	"default": "Defaults",
+	"set":     "EnumSet",
+}
+
+// typeOptions returns opts with the options of a type applied. They are
+// comma-separated flags, without their dash, with values given after an equal
+// sign, which may be left out for booleans, as in "transform=snake, sql". Only
+// the flags setting fields of generator.Options with the same name, other than
+// those about the file as a whole, may be given.
+func typeOptions(opts generator.Options, options string) (generator.Options, error) {
+	for _, option := range strings.Split(options, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+		name, value := option, ""
+		if i := strings.Index(option, "="); i >= 0 {
+			name, value = strings.TrimSpace(option[:i]), strings.TrimSpace(option[i+1:])
+		}
+		if err := setOption(&opts, name, value); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// setOption sets the field of opts set by the named flag to value, which may
+// be empty for booleans. Lists, as for -default, are comma-separated and added
+// to.
+func setOption(opts *generator.Options, name, value string) error {
+	f := optionField(opts, name)
+	if !f.IsValid() {
+		return fmt.Errorf("unknown option %q", name)
+	}
+	switch f.Kind() {
+	case reflect.Bool:
+		b := true
+		if value != "" {
+			var err error
+			if b, err = strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("option %s: %v", name, err)
+			}
+		}
+		f.SetBool(b)
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Slice:
+		for _, s := range strings.Split(value, ",") {
+			f.Set(reflect.Append(f, reflect.ValueOf(s)))
+		}
+	default:
+		return fmt.Errorf("unknown option %q", name)
+	}
+	return nil
+}
+
+// optionField returns the field of opts set by the named flag, or the zero
+// reflect.Value if the flag does not set one or is about the file as a whole.
+func optionField(opts *generator.Options, name string) reflect.Value {
+	switch name {
+	case "gogenerate", "bounds", "template", "displaynames":
+		return reflect.Value{}
+	}
+	if flag.Lookup(name) == nil {
+		return reflect.Value{}
+	}
+	field := typeOptionFields[name]
+	if field == "" {
+		field = name
+	}
+	return reflect.ValueOf(opts).Elem().FieldByNameFunc(func(s string) bool { return strings.EqualFold(s, field) })
+}
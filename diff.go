@@ -0,0 +1,174 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// A diffOp is a line of a diff: kept, deleted or inserted.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'.
+	line string
+}
+
+// unifiedDiff returns the changes turning have into want in the unified
+// format, with both named path, or the empty string if they are equal.
+func unifiedDiff(path string, have, want []byte) string {
+	if bytes.Equal(have, want) {
+		return ""
+	}
+	ops := diffLines(splitLines(have), splitLines(want))
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", path, path)
+	// haveLine and wantLine are the numbers of the lines before ops[i].
+	haveLine, wantLine := 0, 0
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			haveLine++
+			wantLine++
+			i++
+			continue
+		}
+		// A hunk starts with the context before the change and extends
+		// until more than twice the context separates two changes.
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for unchanged := 0; end < len(ops) && unchanged <= 2*diffContext; end++ {
+			if ops[end].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		for end > i && ops[end-1].kind == ' ' && trailing(ops[i:end]) > diffContext {
+			end--
+		}
+		haveStart, wantStart := haveLine-(i-start), wantLine-(i-start)
+		var haveCount, wantCount int
+		var body strings.Builder
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				haveCount++
+			}
+			if op.kind != '-' {
+				wantCount++
+			}
+			fmt.Fprintf(&body, "%c%s\n", op.kind, op.line)
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n%s", hunkRange(haveStart, haveCount), hunkRange(wantStart, wantCount), body.String())
+		for _, op := range ops[i:end] {
+			if op.kind != '+' {
+				haveLine++
+			}
+			if op.kind != '-' {
+				wantLine++
+			}
+		}
+		i = end
+	}
+	return buf.String()
+}
+
+// trailing returns the number of kept lines at the end of ops.
+func trailing(ops []diffOp) int {
+	n := 0
+	for n < len(ops) && ops[len(ops)-1-n].kind == ' ' {
+		n++
+	}
+	return n
+}
+
+// hunkRange formats the start and length of the lines of a hunk in one file,
+// numbering lines from 1 as the unified format does.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines returns the lines of data, without their newlines.
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// diffLines returns a shortest sequence of kept, deleted and inserted lines
+// turning a into b. The common prefix and suffix are set aside first, since
+// regenerated files usually differ in a few places only.
+func diffLines(a, b []string) []diffOp {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int32, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			ops = append(ops, diffOp{' ', x[i]})
+			i++
+			j++
+		case j == len(y) || i < len(x) && lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', x[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', y[j]})
+			j++
+		}
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
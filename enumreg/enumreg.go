@@ -0,0 +1,119 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package enumreg is a registry of the types generated for by jsonenums with
+// -registry, which register themselves when their package is initialized. It
+// lets tools that only know the names of the types, such as admin pages and
+// form builders, list and parse their values:
+//
+//	v, err := enumreg.Parse("Status", "Active")
+//
+// A type is named either by its name alone, as long as no other registered
+// type has the same, or by its name qualified by the import path of its
+// package, as in "github.com/me/proj/api.Status".
+package enumreg
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// An Enum describes a registered type.
+type Enum struct {
+	Package string   // The import path of the package of the type.
+	Name    string   // The name of the type.
+	Names   []string // The names of the values of the type, one for each.
+
+	// Parse returns the value of the type with the given name, if any.
+	Parse func(name string) (interface{}, bool)
+}
+
+// QualifiedName returns the name of the type qualified by the import path of
+// its package.
+func (e *Enum) QualifiedName() string {
+	return e.Package + "." + e.Name
+}
+
+var (
+	mu sync.RWMutex
+	// enums holds the registered types by qualified name, and byName by name,
+	// several of them if the name is ambiguous.
+	enums  = make(map[string]*Enum)
+	byName = make(map[string][]*Enum)
+)
+
+// Register adds e to the registry. It is called by the generated code, and
+// panics if a type with the same qualified name is already registered.
+func Register(e Enum) {
+	mu.Lock()
+	defer mu.Unlock()
+	q := e.QualifiedName()
+	if _, ok := enums[q]; ok {
+		panic("enumreg: type " + q + " registered twice")
+	}
+	enums[q] = &e
+	byName[e.Name] = append(byName[e.Name], &e)
+}
+
+// Lookup returns the registered type with the given name or qualified name.
+func Lookup(typeName string) (*Enum, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if e, ok := enums[typeName]; ok {
+		return e, nil
+	}
+	switch found := byName[typeName]; len(found) {
+	case 0:
+		return nil, fmt.Errorf("enumreg: unknown type %q", typeName)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("enumreg: ambiguous type %q, defined in %s and %s",
+			typeName, found[0].Package, found[1].Package)
+	}
+}
+
+// Parse returns the value of the named type with the given name.
+func Parse(typeName, name string) (interface{}, error) {
+	e, err := Lookup(typeName)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := e.Parse(name)
+	if !ok {
+		return nil, fmt.Errorf("enumreg: invalid %s %q", e.Name, name)
+	}
+	return v, nil
+}
+
+// Names returns the names of the values of the named type.
+func Names(typeName string) ([]string, error) {
+	e, err := Lookup(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), e.Names...), nil
+}
+
+// Types returns the qualified names of the registered types, sorted.
+func Types() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(enums))
+	for q := range enums {
+		names = append(names, q)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -7,50 +7,74 @@ import (
 	"fmt"
 )
 
-var (
-	_ShirtSizeNameToValue = map[string]ShirtSize{
-		"NA": NA,
-		"XS": XS,
-		"S":  S,
-		"M":  M,
-		"L":  L,
-		"XL": XL,
+// _ShirtSizeName returns the name of v, if it has one.
+func _ShirtSizeName(v ShirtSize) (string, bool) {
+	switch v {
+	case NA:
+		return "NA", true
+	case XS:
+		return "XS", true
+	case S:
+		return "S", true
+	case M:
+		return "M", true
+	case L:
+		return "L", true
+	case XL:
+		return "XL", true
 	}
+	return "", false
+}
 
-	_ShirtSizeValueToName = map[ShirtSize]string{
-		NA: "NA",
-		XS: "XS",
-		S:  "S",
-		M:  "M",
-		L:  "L",
-		XL: "XL",
+// _ShirtSizeValue returns the value with name s, if any.
+func _ShirtSizeValue(s string) (ShirtSize, bool) {
+	switch s {
+	case "NA":
+		return NA, true
+	case "XS":
+		return XS, true
+	case "S":
+		return S, true
+	case "M":
+		return M, true
+	case "L":
+		return L, true
+	case "XL":
+		return XL, true
 	}
-)
-
-func init() {
 	var v ShirtSize
-	if _, ok := interface{}(v).(fmt.Stringer); ok {
-		_ShirtSizeNameToValue = map[string]ShirtSize{
-			interface{}(NA).(fmt.Stringer).String(): NA,
-			interface{}(XS).(fmt.Stringer).String(): XS,
-			interface{}(S).(fmt.Stringer).String():  S,
-			interface{}(M).(fmt.Stringer).String():  M,
-			interface{}(L).(fmt.Stringer).String():  L,
-			interface{}(XL).(fmt.Stringer).String(): XL,
-		}
-	}
+	return v, false
+}
+
+// _ShirtSizeJSON holds the marshaled names, in the order of the cases of
+// MarshalJSON, so that it does not allocate.
+var _ShirtSizeJSON = [...][]byte{
+	[]byte("\"NA\""),
+	[]byte("\"XS\""),
+	[]byte("\"S\""),
+	[]byte("\"M\""),
+	[]byte("\"L\""),
+	[]byte("\"XL\""),
 }
 
 // MarshalJSON is generated so ShirtSize satisfies json.Marshaler.
+// The returned slice is shared and must not be modified.
 func (r ShirtSize) MarshalJSON() ([]byte, error) {
-	if s, ok := interface{}(r).(fmt.Stringer); ok {
-		return json.Marshal(s.String())
-	}
-	s, ok := _ShirtSizeValueToName[r]
-	if !ok {
-		return nil, fmt.Errorf("invalid ShirtSize: %d", r)
+	switch r {
+	case NA:
+		return _ShirtSizeJSON[0], nil
+	case XS:
+		return _ShirtSizeJSON[1], nil
+	case S:
+		return _ShirtSizeJSON[2], nil
+	case M:
+		return _ShirtSizeJSON[3], nil
+	case L:
+		return _ShirtSizeJSON[4], nil
+	case XL:
+		return _ShirtSizeJSON[5], nil
 	}
-	return json.Marshal(s)
+	return nil, fmt.Errorf("invalid ShirtSize: %d", r)
 }
 
 // UnmarshalJSON is generated so ShirtSize satisfies json.Unmarshaler.
@@ -59,7 +83,7 @@ func (r *ShirtSize) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &s); err != nil {
 		return fmt.Errorf("ShirtSize should be a string, got %s", data)
 	}
-	v, ok := _ShirtSizeNameToValue[s]
+	v, ok := _ShirtSizeValue(s)
 	if !ok {
 		return fmt.Errorf("invalid ShirtSize %q", s)
 	}
@@ -17,17 +17,34 @@ var (
 		"Saturday":  Saturday,
 		"Sunday":    Sunday,
 	}
+)
 
-	_WeekDayValueToName = map[WeekDay]string{
-		Monday:    "Monday",
-		Tuesday:   "Tuesday",
-		Wednesday: "Wednesday",
-		Thursday:  "Thursday",
-		Friday:    "Friday",
-		Saturday:  "Saturday",
-		Sunday:    "Sunday",
+// _WeekDayName returns the name of v, if it has one.
+func _WeekDayName(v WeekDay) (string, bool) {
+	switch v {
+	case Monday:
+		return "Monday", true
+	case Tuesday:
+		return "Tuesday", true
+	case Wednesday:
+		return "Wednesday", true
+	case Thursday:
+		return "Thursday", true
+	case Friday:
+		return "Friday", true
+	case Saturday:
+		return "Saturday", true
+	case Sunday:
+		return "Sunday", true
 	}
-)
+	return "", false
+}
+
+// _WeekDayValue returns the value with name s, if any.
+func _WeekDayValue(s string) (WeekDay, bool) {
+	v, ok := _WeekDayNameToValue[s]
+	return v, ok
+}
 
 func init() {
 	var v WeekDay
@@ -44,16 +61,41 @@ func init() {
 	}
 }
 
+// _WeekDayJSON holds the marshaled names, in the order of the cases of
+// MarshalJSON, so that it does not allocate.
+var _WeekDayJSON = [...][]byte{
+	[]byte("\"Monday\""),
+	[]byte("\"Tuesday\""),
+	[]byte("\"Wednesday\""),
+	[]byte("\"Thursday\""),
+	[]byte("\"Friday\""),
+	[]byte("\"Saturday\""),
+	[]byte("\"Sunday\""),
+}
+
 // MarshalJSON is generated so WeekDay satisfies json.Marshaler.
+// The returned slice is shared and must not be modified.
 func (r WeekDay) MarshalJSON() ([]byte, error) {
 	if s, ok := interface{}(r).(fmt.Stringer); ok {
 		return json.Marshal(s.String())
 	}
-	s, ok := _WeekDayValueToName[r]
-	if !ok {
-		return nil, fmt.Errorf("invalid WeekDay: %d", r)
+	switch r {
+	case Monday:
+		return _WeekDayJSON[0], nil
+	case Tuesday:
+		return _WeekDayJSON[1], nil
+	case Wednesday:
+		return _WeekDayJSON[2], nil
+	case Thursday:
+		return _WeekDayJSON[3], nil
+	case Friday:
+		return _WeekDayJSON[4], nil
+	case Saturday:
+		return _WeekDayJSON[5], nil
+	case Sunday:
+		return _WeekDayJSON[6], nil
 	}
-	return json.Marshal(s)
+	return nil, fmt.Errorf("invalid WeekDay: %d", r)
 }
 
 // UnmarshalJSON is generated so WeekDay satisfies json.Unmarshaler.
@@ -62,7 +104,7 @@ func (r *WeekDay) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &s); err != nil {
 		return fmt.Errorf("WeekDay should be a string, got %s", data)
 	}
-	v, ok := _WeekDayNameToValue[s]
+	v, ok := _WeekDayValue(s)
 	if !ok {
 		return fmt.Errorf("invalid WeekDay %q", s)
 	}
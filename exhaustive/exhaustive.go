@@ -0,0 +1,142 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exhaustive defines an analyzer reporting switch statements over the
+// types generated for by jsonenums that miss some of their constants.
+//
+// The types are those marked with the parser.GenerateDirective, or with
+// methods declared in files generated by jsonenums. Their constants are found
+// as jsonenums finds them, leaving out those marked with the
+// parser.SkipDirective, so a switch statement does not need to handle
+// sentinels. A switch statement with a default case is always exhaustive.
+package exhaustive
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/davars/jsonenums/parser"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports switch statements over the types generated for by
+// jsonenums that miss some of their constants.
+var Analyzer = &analysis.Analyzer{
+	Name:      "exhaustive",
+	Doc:       "check that switch statements over jsonenums types handle all their constants",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(enumFact)},
+}
+
+// An enumFact is attached to the types generated for by jsonenums, so that
+// switch statements in other packages are checked too.
+type enumFact struct {
+	Names  []string // The constants, in declaration order.
+	Values []string // The exact representation of the value of each constant.
+}
+
+func (*enumFact) AFact() {}
+
+func (f *enumFact) String() string {
+	return "enum " + strings.Join(f.Names, ",")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	pkg := parser.NewPackage(pass.Fset, pass.Files, pass.Pkg, pass.TypesInfo)
+	seen := make(map[types.Object]bool)
+	for _, typeName := range append(pkg.MarkedTypes(), pkg.GeneratedTypes()...) {
+		// Facts are attached to the type, not to its aliases.
+		tn, ok := pass.Pkg.Scope().Lookup(typeName).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		named, ok := tn.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() != pass.Pkg || seen[named.Obj()] {
+			continue
+		}
+		obj := named.Obj()
+		seen[obj] = true
+		values, err := pkg.Values(typeName)
+		if err != nil {
+			// The errors are reported by jsonenums itself.
+			continue
+		}
+		fact := new(enumFact)
+		for _, v := range values {
+			if v.Skip {
+				continue
+			}
+			fact.Names = append(fact.Names, v.Name)
+			fact.Values = append(fact.Values, v.Value.ExactString())
+		}
+		pass.ExportObjectFact(obj, fact)
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.SwitchStmt)(nil)}, func(n ast.Node) {
+		stmt := n.(*ast.SwitchStmt)
+		if stmt.Tag == nil {
+			return
+		}
+		named, ok := pass.TypesInfo.TypeOf(stmt.Tag).(*types.Named)
+		if !ok {
+			return
+		}
+		var fact enumFact
+		if !pass.ImportObjectFact(named.Obj(), &fact) {
+			return
+		}
+		handled := make(map[string]bool)
+		for _, clause := range stmt.Body.List {
+			clause := clause.(*ast.CaseClause) // Guaranteed to succeed in a switch statement.
+			if clause.List == nil {
+				// A default case handles every value.
+				return
+			}
+			for _, expr := range clause.List {
+				if tv, ok := pass.TypesInfo.Types[expr]; ok && tv.Value != nil {
+					handled[tv.Value.ExactString()] = true
+				}
+			}
+		}
+		var missing []string
+		for i, name := range fact.Names {
+			if !handled[fact.Values[i]] {
+				missing = append(missing, name)
+				// Aliases of the constant are missing too, but naming
+				// one of them is enough.
+				handled[fact.Values[i]] = true
+			}
+		}
+		if len(missing) > 0 {
+			pass.Reportf(stmt.Pos(), "missing cases in switch of type %s: %s",
+				typeString(named, pass.Pkg), strings.Join(missing, ", "))
+		}
+	})
+	return nil, nil
+}
+
+// typeString returns the name of the type, qualified by its package unless it
+// is pkg.
+func typeString(named *types.Named, pkg *types.Package) string {
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg() == pkg {
+		return obj.Name()
+	}
+	return fmt.Sprintf("%s.%s", obj.Pkg().Name(), obj.Name())
+}
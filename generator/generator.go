@@ -0,0 +1,926 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package generator generates the code written by jsonenums, so that other
+// code generators can embed it instead of running the command.
+//
+// For example, the following generates the methods of the Pill type of the
+// package in the current directory, with names in snake case:
+//
+//	pkg, err := parser.ParsePackage(".")
+//	if err != nil {
+//		return err
+//	}
+//	src, err := generator.Generate(pkg, []string{"Pill"}, generator.Options{
+//		Transform: "snake",
+//	})
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/davars/jsonenums/parser"
+)
+
+// maxSwitchValues is the largest number of distinct values of a type for
+// which the auto lookup uses switch statements rather than maps.
+const maxSwitchValues = 16
+
+// Options configures the generated code. The zero value generates the JSON
+// methods only, using the names of the constants as they are.
+type Options struct {
+	// Command is recorded in the header of the generated files, as in
+	// "generated by jsonenums <Command>; DO NOT EDIT".
+	Command string
+
+	// GoGenerate, if not empty, holds the arguments of a //go:generate
+	// directive running jsonenums, added to the generated file so that
+	// go generate regenerates it.
+	GoGenerate string
+
+	TrimPrefix  string // Trimmed from the constant names.
+	Transform   string // Case style applied to the constant names; one of TransformNames.
+	LineComment bool   // Whether line comments override the constant names.
+
+	// Char represents the constants of integer types whose values are
+	// written as rune literals by their characters instead of their names,
+	// so that Spades Suit = '♠' is marshaled as "♠".
+	Char bool
+
+	// Unknown is how unknown names are unmarshaled: error (the default if
+	// empty), zero, default or keep. With default, they are unmarshaled as
+	// the constant of each type listed in Defaults.
+	Unknown  string
+	Defaults []string
+	// Null is how a JSON null is unmarshaled, with the same choices as
+	// Unknown. With error, the default, it is rejected as the empty name is.
+	Null string
+
+	Text    bool // Generate MarshalText and UnmarshalText.
+	JSONv2  bool // Generate MarshalJSONTo and UnmarshalJSONFrom for encoding/json/v2.
+	Append  bool // Generate AppendJSON and AppendText.
+	YAML    bool // Generate MarshalYAML and UnmarshalYAML for gopkg.in/yaml.v3.
+	XML     bool // Generate MarshalXML, UnmarshalXML and their attribute counterparts.
+	GQL     bool // Generate MarshalGQL and UnmarshalGQL for github.com/99designs/gqlgen.
+	MsgPack bool // Generate EncodeMsgpack and DecodeMsgpack for github.com/vmihailenco/msgpack/v5.
+	CBOR    bool // Generate MarshalCBOR and UnmarshalCBOR for github.com/fxamacker/cbor/v2.
+	BSON    bool // Generate MarshalBSONValue and UnmarshalBSONValue for go.mongodb.org/mongo-driver/v2.
+	SQL     bool // Generate Value and Scan.
+	Flag    bool // Generate Set and, if missing, String.
+	Values  bool // Generate TValues and TNames.
+	All     bool // Generate TAll, which needs Go 1.23.
+	IsValid bool // Generate IsValid.
+	Parse   bool // Generate ParseT and MustParseT.
+	Slices  bool // Generate ParseTSlice and TSliceStrings.
+	Random  bool // Generate RandomT.
+	Quick   bool // Generate Generate for testing/quick.
+	Switch  bool // Generate Switch.
+	Cycle   bool // Generate Next and Prev.
+
+	// Registry registers the types with the enumreg package when their
+	// package is initialized.
+	Registry bool
+
+	// Bounds, if not empty, holds the formats of the names of the constants
+	// generated for the lowest and highest values of each type and for its
+	// number of distinct values, in that order, with %s standing for the
+	// name of the type, as in "_%sMin", "_%sMax", "%sCount".
+	Bounds []string
+
+	// DisplayNames, if not empty, generates DisplayName, returning the
+	// display names of the constants held by DisplayNames[lang][type][name].
+	DisplayNames map[string]map[string]map[string]string
+
+	// Gob generates GobEncode, GobDecode and RegisterTGob, encoding values
+	// as their names so they survive renumbering the constants.
+	Gob bool
+
+	// Binary, if not empty, generates MarshalBinary and UnmarshalBinary,
+	// encoding values as their names, or as 8-byte big-endian integers
+	// with int.
+	Binary string
+
+	GoString     bool // Generate GoString.
+	Description  bool // Generate Description from the doc comments.
+	IsDeprecated bool // Generate IsDeprecated.
+
+	// BitFlags makes the JSON methods treat the constants of integer types
+	// as flags, marshaling a value as the list of the names of its flags.
+	BitFlags bool
+
+	// Marshal is how MarshalJSON represents values: as names, the default
+	// if empty, or as numbers, which also sets AcceptNumbers.
+	Marshal string
+
+	// Receiver is the receiver of the generated methods that do not modify
+	// the value, such as MarshalJSON: value, the default if empty, or
+	// pointer, matching types whose other methods have pointer receivers.
+	Receiver string
+
+	// Order is the order of the constants in the generated code: declaration,
+	// the default if empty, which follows the files of the package, value or
+	// name, which do not change when the constants move between files.
+	Order string
+
+	// Lookup is how the generated methods look names and values up: with
+	// switch statements, with maps, or with array, indexing an array of
+	// names by value and searching a sorted array of names, for types whose
+	// values are consecutive integers, such as those declared with iota or
+	// starting from a negative Unknown constant. With auto, the default if
+	// empty, switch statements are used for types with at most 16 distinct
+	// values, then arrays if possible, then maps.
+	Lookup string
+
+	// AcceptNumbers makes UnmarshalJSON accept, for integer types, a JSON
+	// number equal to one of the constants as well as the names.
+	AcceptNumbers bool
+
+	// AcceptDeprecated makes deprecated constants, whose doc comment has a
+	// paragraph starting with "Deprecated: ", be accepted when unmarshaling
+	// but never used when marshaling.
+	AcceptDeprecated bool
+
+	// Ent generates Values and Validate for the enum fields of
+	// entgo.io/ent, together with the methods of SQL and, if missing,
+	// String, through which ent stores and validates the names.
+	Ent bool
+
+	// Pgx generates TextValue, ScanText and RegisterTPgx, mapping values
+	// to the labels of a Postgres enum with github.com/jackc/pgx/v5.
+	Pgx bool
+
+	// Jsoniter generates RegisterTJsoniter, registering encoder and decoder
+	// functions calling MarshalJSON and UnmarshalJSON with
+	// github.com/json-iterator/go.
+	Jsoniter bool
+
+	// EasyJSON generates MarshalEasyJSON and UnmarshalEasyJSON for
+	// github.com/mailru/easyjson, calling MarshalJSON and UnmarshalJSON.
+	EasyJSON bool
+
+	// Gorm generates GormDataType for gorm.io/gorm, together with the
+	// methods of SQL, whose Scan then also accepts integers formatted as
+	// text, as some drivers return legacy integer columns.
+	Gorm bool
+
+	// MapKeys generates MarshalText and UnmarshalText, as Text does, so
+	// encoding/json uses the names as the keys of maps keyed by the type.
+	// GenerateTests then also checks that such maps round-trip.
+	MapKeys bool
+
+	// TOML generates MarshalText and UnmarshalText, through which the
+	// github.com/BurntSushi/toml and github.com/pelletier/go-toml/v2
+	// libraries encode values, as Text does. GenerateTOMLTests then
+	// generates tests checking both libraries.
+	TOML bool
+
+	// Bind generates UnmarshalParam, through which github.com/labstack/echo/v4
+	// and github.com/gin-gonic/gin bind request parameters, together with the
+	// methods of Text.
+	Bind bool
+
+	// Env generates Decode, through which github.com/kelseyhightower/envconfig
+	// decodes environment variables, together with the methods of Text,
+	// through which github.com/caarlos0/env does.
+	Env bool
+
+	// PFlag generates Type and TFlagHelp, through which T satisfies
+	// pflag.Value of github.com/spf13/pflag, together with the methods of
+	// Flag.
+	PFlag bool
+
+	// CLI generates a TFlag type, a flag of github.com/urfave/cli/v2 holding
+	// a T, together with the methods of Flag.
+	CLI bool
+
+	// Kong generates Decode, through which T satisfies kong.MapperValue of
+	// github.com/alecthomas/kong. It cannot be used with Env, whose Decode
+	// method has another signature.
+	Kong bool
+
+	// Cobra generates TCompletion, completing the names of flags and
+	// arguments of github.com/spf13/cobra commands.
+	Cobra bool
+
+	// EnumSet generates a TSet type holding sets of values, marshaled to
+	// JSON as lists of names. It is a bit set for types with at most 64
+	// values and a map otherwise.
+	EnumSet bool
+
+	Tests bool // Generate round-trip tests with GenerateTests.
+	Fuzz  bool // Generate UnmarshalJSON fuzz targets with GenerateTests.
+
+	// Benchmarks generates benchmarks of MarshalJSON and UnmarshalJSON with
+	// GenerateTests.
+	Benchmarks bool
+
+	// Template, if not nil, is executed by Generate instead of the built-in
+	// template. It receives the options together with the PackageName, the
+	// PackagePath and the Types, a list of Enum. Use ParseTemplate to make
+	// the helper functions returned by Funcs available to it.
+	Template *template.Template
+}
+
+// An Enum is one of the requested types together with its constants.
+type Enum struct {
+	Name       string  // The name of the type.
+	IsString   bool    // Whether the underlying type is a string rather than an integer.
+	IsUnsigned bool    // Whether the underlying type is an unsigned integer.
+	IsFloat    bool    // Whether the underlying type is a floating-point number.
+	HasString  bool    // Whether the type declares its own String method.
+	Values     []Value // The constants defined for the type, in the order set by Options.Order.
+	Unknown    string  // How unknown names are unmarshaled: error, zero, default or keep.
+	Null       string  // How a JSON null is unmarshaled: error, zero, default or keep.
+	Default    string  // The constant unknown names or null are unmarshaled as with Unknown or Null set to default.
+	Lookup     string  // How names and values are looked up: switch, map or array.
+
+	// Deprecated holds a constant for each value of the type that is only
+	// held by deprecated constants.
+	Deprecated []string
+
+	// Translations holds the display names of the constants in each of the
+	// languages of Options.DisplayNames naming some, sorted by language.
+	Translations []Translation
+}
+
+// A Translation holds the display names of the constants of a type in a
+// language.
+type Translation struct {
+	Lang string
+	// Labels maps the canonical constants, by OriginalName, to their display
+	// names, given for them or for one of their aliases.
+	Labels map[string]string
+}
+
+// Names returns the names of the constants of e, in the order of Values.
+func (e Enum) Names() []string {
+	names := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		names[i] = v.Name
+	}
+	return names
+}
+
+// AcceptedNames returns the names UnmarshalJSON accepts for e, each once, in
+// the order of Values: the name of each constant, unless an earlier one has
+// it, followed by the other names it accepts.
+func (e Enum) AcceptedNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, v := range e.Values {
+		for _, name := range append([]string{v.Name}, v.Accept...) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// CanonicalValues returns the canonical constants of e, one for each distinct
+// value, in the order of Values.
+func (e Enum) CanonicalValues() []Value {
+	var values []Value
+	for _, v := range e.Values {
+		if v.Canonical {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// SortedValues returns the constants of e that are looked up by name, one for
+// each distinct name, sorted by name. A constant appears again for each of the
+// names it accepts, as its Name.
+func (e Enum) SortedValues() []Value {
+	var values []Value
+	for _, v := range e.Values {
+		if !v.Shadowed {
+			values = append(values, v)
+		}
+		for _, name := range v.Accept {
+			a := v
+			a.Name, a.Accept = name, nil
+			values = append(values, a)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+	return values
+}
+
+// dense reports whether the distinct values of e are consecutive integers, so
+// that their offsets from the lowest one can index an array.
+func (e Enum) dense() bool {
+	if e.IsString || e.IsFloat {
+		return false
+	}
+	values := e.CanonicalValues()
+	min, ok := e.minValue()
+	if !ok {
+		return false
+	}
+	seen := make([]bool, len(values))
+	for _, v := range values {
+		d, ok := constant.Uint64Val(constant.BinaryOp(v.value, token.SUB, min.value))
+		if !ok || d >= uint64(len(values)) || seen[d] {
+			return false
+		}
+		seen[d] = true
+	}
+	return true
+}
+
+// minValue returns the canonical constant of e with the lowest value.
+func (e Enum) minValue() (Value, bool) {
+	values := e.CanonicalValues()
+	if len(values) == 0 {
+		return Value{}, false
+	}
+	min := values[0]
+	for _, v := range values[1:] {
+		if constant.Compare(v.value, token.LSS, min.value) {
+			min = v
+		}
+	}
+	return min, true
+}
+
+// Min returns the name of the constant holding the lowest value of e.
+func (e Enum) Min() string {
+	min := e.Values[0]
+	for _, v := range e.Values[1:] {
+		if constant.Compare(v.value, token.LSS, min.value) {
+			min = v
+		}
+	}
+	return min.OriginalName
+}
+
+// Max returns the name of the constant holding the highest value of e.
+func (e Enum) Max() string {
+	max := e.Values[0]
+	for _, v := range e.Values[1:] {
+		if constant.Compare(v.value, token.GTR, max.value) {
+			max = v
+		}
+	}
+	return max.OriginalName
+}
+
+// Count returns the number of distinct values of e.
+func (e Enum) Count() int {
+	n := 0
+	for _, v := range e.Values {
+		if !v.Alias {
+			n++
+		}
+	}
+	return n
+}
+
+// DeclaredValues returns the constants of e in declaration order, whatever the
+// order set by Options.Order.
+func (e Enum) DeclaredValues() []Value {
+	values := append([]Value(nil), e.Values...)
+	sort.Slice(values, func(i, j int) bool { return values[i].index < values[j].index })
+	return values
+}
+
+// ArrayBase returns the name of the constant whose value is at index 0 of the
+// array of names, or an empty string if that value is 0. The array is only
+// generated for types with the array lookup.
+func (e Enum) ArrayBase() string {
+	min, ok := e.minValue()
+	if !ok || constant.Sign(min.value) == 0 {
+		return ""
+	}
+	return min.OriginalName
+}
+
+// ArrayValues returns the canonical constants of e sorted by value, the order
+// of the array of names when ArrayBase is not empty.
+func (e Enum) ArrayValues() []Value {
+	values := e.CanonicalValues()
+	sort.SliceStable(values, func(i, j int) bool {
+		return constant.Compare(values[i].value, token.LSS, values[j].value)
+	})
+	return values
+}
+
+// translations returns the translations of e out of the display names of
+// Options.DisplayNames.
+func (e Enum) translations(displayNames map[string]map[string]map[string]string) ([]Translation, error) {
+	var langs []string
+	for lang := range displayNames {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	// The label of a value may be given for any of its constants, and is
+	// attached to the canonical one.
+	canonical := make(map[string]string)
+	for _, v := range e.CanonicalValues() {
+		canonical[v.value.ExactString()] = v.OriginalName
+	}
+	byName := make(map[string]string)
+	for _, v := range e.Values {
+		byName[v.OriginalName] = canonical[v.value.ExactString()]
+	}
+
+	var translations []Translation
+	for _, lang := range langs {
+		labels := displayNames[lang][e.Name]
+		if len(labels) == 0 {
+			continue
+		}
+		t := Translation{Lang: lang, Labels: make(map[string]string)}
+		for name := range labels {
+			if _, ok := byName[name]; !ok {
+				return nil, fmt.Errorf("display name in %s for %s, which is not a constant of type %s", lang, name, e.Name)
+			}
+		}
+		for _, v := range e.Values {
+			label, ok := labels[v.OriginalName]
+			c := byName[v.OriginalName]
+			if !ok || c == "" {
+				continue
+			}
+			if _, done := t.Labels[c]; !done || v.Canonical {
+				t.Labels[c] = label
+			}
+		}
+		translations = append(translations, t)
+	}
+	return translations, nil
+}
+
+// UnknownName returns a name that does not belong to any of the constants of e.
+func (e Enum) UnknownName() string {
+	known := make(map[string]bool)
+	for _, v := range e.Values {
+		known[v.Name] = true
+		for _, name := range v.Accept {
+			known[name] = true
+		}
+	}
+	name := "_unknown"
+	for known[name] {
+		name += "_"
+	}
+	return name
+}
+
+// nameRules describes how the names of the constants are computed from those
+// of the Go constants, with the given prefix trimmed and transform applied.
+func nameRules(trimPrefix, transform string) string {
+	switch {
+	case trimPrefix == "":
+		return fmt.Sprintf("with the %s transform", transform)
+	case transform == "none":
+		return fmt.Sprintf("with the prefix %q trimmed", trimPrefix)
+	}
+	return fmt.Sprintf("with the prefix %q trimmed and the %s transform", trimPrefix, transform)
+}
+
+// A Value is a constant of one of the requested types.
+type Value struct {
+	OriginalName string // The name of the constant in the Go source.
+	Name         string // The name used in the generated marshalers.
+	Description  string // The doc comment of the constant, on a single line.
+	Deprecated   bool   // Whether the constant is deprecated.
+
+	// Canonical is set for the single constant, out of those holding the
+	// same value, whose name is used when marshaling the value.
+	Canonical bool
+	// Alias is set if an earlier constant holds the same value.
+	Alias bool
+	// Shadowed is set if an earlier constant has the same Name, which
+	// happens for aliases of string constants.
+	Shadowed bool
+	// Accept holds the names, other than Name, that are unmarshaled as the
+	// constant, as given by the parser.AcceptDirective.
+	Accept []string
+	// Proto holds the protobuf number of the constant given by the
+	// parser.ProtoDirective, in decimal, if any.
+	Proto string
+
+	canonical bool           // Whether the constant is marked as canonical in the source.
+	value     constant.Value // The value of the constant.
+	index     int            // The position of the constant in declaration order.
+}
+
+// ExactValue returns an exact representation of the value of v, the same for
+// all the constants holding that value.
+func (v Value) ExactValue() string {
+	return v.value.ExactString()
+}
+
+// MarshaledName returns the name of v as marshaled to JSON, quoted as a Go
+// string literal, so that the generated code holds it ready to be returned.
+func (v Value) MarshaledName() string {
+	data, err := json.Marshal(v.Name)
+	if err != nil {
+		// Strings always marshal.
+		panic(err)
+	}
+	return strconv.Quote(string(data))
+}
+
+// Enums returns the enums for the named types of pkg, with the names of their
+// constants computed as configured by opts.
+func Enums(pkg *parser.Package, typeNames []string, opts Options) ([]Enum, error) {
+	transform := opts.Transform
+	if transform == "" {
+		transform = "none"
+	}
+	transformFunc, ok := transforms[transform]
+	if !ok {
+		return nil, fmt.Errorf("unknown transform %q; must be one of %s", transform,
+			strings.Join(TransformNames(), ", "))
+	}
+	switch opts.Marshal {
+	case "", "name":
+	case "number":
+		opts.AcceptNumbers = true
+	default:
+		return nil, fmt.Errorf("unknown representation %q of marshaled values; must be one of name, number", opts.Marshal)
+	}
+	switch opts.Binary {
+	case "", "name", "int":
+	default:
+		return nil, fmt.Errorf("unknown binary encoding %q; must be one of name, int", opts.Binary)
+	}
+	switch opts.Order {
+	case "", "declaration", "value", "name":
+	default:
+		return nil, fmt.Errorf("unknown order %q; must be one of declaration, value, name", opts.Order)
+	}
+	switch opts.Lookup {
+	case "", "auto", "switch", "map", "array":
+	default:
+		return nil, fmt.Errorf("unknown lookup %q; must be one of auto, switch, map, array", opts.Lookup)
+	}
+	switch opts.Receiver {
+	case "", "value", "pointer":
+	default:
+		return nil, fmt.Errorf("unknown receiver %q; must be one of value, pointer", opts.Receiver)
+	}
+	if opts.Kong && opts.Env {
+		return nil, fmt.Errorf("kong and env both generate a Decode method and cannot be used together")
+	}
+	if opts.Bounds != nil {
+		if len(opts.Bounds) != 3 {
+			return nil, fmt.Errorf("bounds need 3 names, of the minimum, maximum and count; got %d", len(opts.Bounds))
+		}
+		for _, f := range opts.Bounds {
+			if strings.Count(f, "%") != 1 || !strings.Contains(f, "%s") {
+				return nil, fmt.Errorf("bound name %q must hold %%s, standing for the type, and no other %%", f)
+			}
+		}
+	}
+	unknown, null := opts.Unknown, opts.Null
+	for _, h := range []*string{&unknown, &null} {
+		switch *h {
+		case "":
+			*h = "error"
+		case "error", "zero", "default", "keep":
+		default:
+			return nil, fmt.Errorf("unknown handling %q of unknown names or null; must be one of error, zero, default, keep", *h)
+		}
+	}
+
+	// The constants of all the types are checked before giving up, so that
+	// every problem is reported at once.
+	var problems []string
+	var enums []Enum
+	for _, typeName := range typeNames {
+		consts, err := pkg.Values(typeName)
+		if list, ok := err.(parser.ErrorList); ok {
+			for _, e := range list {
+				problems = append(problems, e.Error())
+			}
+			continue
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("finding values for type %v: %v", typeName, err))
+			continue
+		}
+		e := Enum{
+			Name:       typeName,
+			IsString:   consts[0].Value.Kind() == constant.String,
+			IsUnsigned: consts[0].Unsigned,
+			IsFloat:    consts[0].Value.Kind() == constant.Float,
+			HasString:  pkg.HasMethod(typeName, "String"),
+			Unknown:    unknown,
+			Null:       null,
+		}
+		if opts.BitFlags && (e.IsString || e.IsFloat) {
+			return nil, fmt.Errorf("type %v cannot hold bit flags as it is not an integer type", typeName)
+		}
+		if opts.Binary == "int" && (e.IsString || e.IsFloat) {
+			return nil, fmt.Errorf("type %v cannot be encoded as an integer as it is not an integer type", typeName)
+		}
+		if opts.Char && (e.IsString || e.IsFloat) {
+			return nil, fmt.Errorf("type %v cannot be represented by characters as it is not an integer type", typeName)
+		}
+		if opts.AcceptNumbers && (e.IsString || opts.BitFlags) {
+			return nil, fmt.Errorf("numbers cannot be accepted for type %v with bit flags or string values", typeName)
+		}
+
+		// Constants holding the same value, identified by its exact
+		// representation, are aliases of each other.
+		byValue := make(map[string][]int)
+		var keys []string
+		byName := make(map[string]string)
+		// owners holds the first constant with each name, and transformed
+		// whether its name is computed from that of the constant.
+		owners := make(map[string]parser.Value)
+		transformed := make(map[string]bool)
+		var collisions []string
+		for _, c := range consts {
+			if c.Skip {
+				continue
+			}
+			name := transformFunc(strings.TrimPrefix(c.Name, opts.TrimPrefix))
+			derived := true
+			if e.IsString {
+				// String constants are represented by their own value.
+				name = constant.StringVal(c.Value)
+				derived = false
+			}
+			if opts.Char && c.Char {
+				r, _ := constant.Int64Val(c.Value)
+				name = string(rune(r))
+				derived = false
+			}
+			if opts.LineComment && c.LineComment != "" {
+				name = c.LineComment
+				derived = false
+			}
+			if c.JSONName != "" {
+				name = c.JSONName
+				derived = false
+			}
+			if opts.MapKeys && e.IsString && name != constant.StringVal(c.Value) {
+				// encoding/json ignores MarshalText for the keys of string types.
+				return nil, fmt.Errorf("%v: constant %v of type %v cannot be used as a JSON map key as its name differs from its value", c.Pos, c.Name, typeName)
+			}
+			key := c.Value.ExactString()
+			k, shadowed := byName[name]
+			if shadowed && k != key {
+				owner := owners[name]
+				msg := fmt.Sprintf("%v: constant %v of type %v has the name %q of constant %v, declared at %v, with a different value",
+					c.Pos, c.Name, typeName, name, owner.Name, owner.Pos)
+				if derived && transformed[name] && (opts.TrimPrefix != "" || transform != "none") {
+					msg += fmt.Sprintf(" (both names are computed %s)", nameRules(opts.TrimPrefix, transform))
+				}
+				collisions = append(collisions, msg)
+				continue
+			}
+			if !shadowed {
+				owners[name] = c
+				transformed[name] = derived
+			}
+			byName[name] = key
+			_, alias := byValue[key]
+			if !alias {
+				keys = append(keys, key)
+			}
+			byValue[key] = append(byValue[key], len(e.Values))
+			e.Values = append(e.Values, Value{
+				OriginalName: c.Name,
+				Name:         name,
+				Description:  strings.Join(strings.Fields(c.Doc), " "),
+				Deprecated:   c.Deprecated,
+				Alias:        alias,
+				Shadowed:     shadowed,
+				Accept:       c.Accept,
+				Proto:        c.Proto,
+				canonical:    c.Canonical,
+				value:        c.Value,
+				index:        len(e.Values),
+			})
+			if contains(opts.Defaults, c.Name) {
+				e.Default = c.Name
+			}
+		}
+
+		if len(collisions) > 0 {
+			problems = append(problems, collisions...)
+			continue
+		}
+
+		// The accepted names are checked against the names of all the
+		// constants, and those of earlier constants with the same value are
+		// dropped.
+		i := 0
+		for _, c := range consts {
+			if c.Skip {
+				continue
+			}
+			v := &e.Values[i]
+			i++
+			var accept []string
+			for _, name := range v.Accept {
+				key := v.value.ExactString()
+				if k, ok := byName[name]; ok {
+					if k != key {
+						return nil, fmt.Errorf("%v: constant %v of type %v accepts the name %q of another constant with a different value", c.Pos, c.Name, typeName, name)
+					}
+					continue
+				}
+				byName[name] = key
+				accept = append(accept, name)
+			}
+			v.Accept = accept
+		}
+
+		// Pick the constant whose name is marshaled for each value: the one
+		// marked as canonical, or else the first declared. With
+		// AcceptDeprecated, deprecated constants are never picked.
+		deprecatedKeys := make(map[string]bool)
+		for _, key := range keys {
+			aliases := byValue[key]
+			canonical, deprecated := -1, true
+			for _, i := range aliases {
+				v := e.Values[i]
+				deprecated = deprecated && v.Deprecated
+				if opts.AcceptDeprecated && v.Deprecated {
+					continue
+				}
+				if canonical < 0 || v.canonical && !e.Values[canonical].canonical {
+					canonical = i
+				}
+			}
+			if canonical >= 0 {
+				e.Values[canonical].Canonical = true
+			}
+			deprecatedKeys[key] = deprecated
+		}
+		switch opts.Order {
+		case "value":
+			sort.SliceStable(e.Values, func(i, j int) bool {
+				return constant.Compare(e.Values[i].value, token.LSS, e.Values[j].value)
+			})
+		case "name":
+			sort.SliceStable(e.Values, func(i, j int) bool { return e.Values[i].Name < e.Values[j].Name })
+		}
+		for _, v := range e.Values {
+			key := v.value.ExactString()
+			if deprecatedKeys[key] {
+				e.Deprecated = append(e.Deprecated, v.OriginalName)
+				deprecatedKeys[key] = false
+			}
+		}
+		if len(e.Values) == 0 {
+			return nil, fmt.Errorf("all the values of type %v are skipped", typeName)
+		}
+		if (unknown == "default" || null == "default") && e.Default == "" {
+			return nil, fmt.Errorf("no default constant given for type %v", typeName)
+		}
+		e.Translations, err = e.translations(opts.DisplayNames)
+		if err != nil {
+			return nil, err
+		}
+		e.Lookup = opts.Lookup
+		switch {
+		case e.Lookup == "array" && !e.dense():
+			return nil, fmt.Errorf("values of type %v cannot be looked up in an array as they are not consecutive integers", typeName)
+		case e.Lookup != "" && e.Lookup != "auto":
+		case len(e.CanonicalValues()) <= maxSwitchValues:
+			e.Lookup = "switch"
+		case e.dense():
+			e.Lookup = "array"
+		default:
+			e.Lookup = "map"
+		}
+		enums = append(enums, e)
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "\n"))
+	}
+	return enums, nil
+}
+
+// Generate returns the source of a file of pkg holding the methods of the
+// named types. If the generated code is not valid Go, which should never
+// happen, it is returned unformatted together with the error.
+func Generate(pkg *parser.Package, typeNames []string, opts Options) ([]byte, error) {
+	if opts.Template != nil {
+		return execute(opts.Template, pkg, typeNames, opts)
+	}
+	return execute(generatedTmpl, pkg, typeNames, opts)
+}
+
+// GenerateTests returns the source of a test file of pkg holding the tests
+// and fuzz targets requested by opts for the named types. If the generated
+// code is not valid Go, it is returned unformatted together with the error.
+func GenerateTests(pkg *parser.Package, typeNames []string, opts Options) ([]byte, error) {
+	return execute(testTmpl, pkg, typeNames, opts)
+}
+
+// GenerateTOMLTests returns the source of a test file of pkg checking that
+// the constants of the named types round-trip through the
+// github.com/BurntSushi/toml and github.com/pelletier/go-toml/v2 libraries.
+// If the generated code is not valid Go, it is returned unformatted together
+// with the error.
+func GenerateTOMLTests(pkg *parser.Package, typeNames []string, opts Options) ([]byte, error) {
+	return execute(tomlTestTmpl, pkg, typeNames, opts)
+}
+
+// execute executes tmpl for the named types and returns the formatted source.
+func execute(tmpl *template.Template, pkg *parser.Package, typeNames []string, opts Options) ([]byte, error) {
+	if opts.Marshal == "number" {
+		opts.AcceptNumbers = true
+	}
+	if opts.TOML || opts.MapKeys || opts.Bind || opts.Env {
+		opts.Text = true
+	}
+	if opts.Ent || opts.Gorm {
+		opts.SQL = true
+	}
+	if opts.PFlag || opts.CLI {
+		opts.Flag = true
+	}
+	enums, err := Enums(pkg, typeNames, opts)
+	if err != nil {
+		return nil, err
+	}
+	data := struct {
+		Options
+		PackageName string
+		PackagePath string
+		Types       []Enum
+	}{opts, pkg.Name, pkg.Path, enums}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("generating code: %v", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("invalid Go generated: %v", err)
+	}
+	return src, nil
+}
+
+// Funcs returns the helper functions available to templates parsed with
+// ParseTemplate:
+//
+//	lower, upper          change the case of a string
+//	snake, screamingSnake convert an identifier to in_progress or IN_PROGRESS
+//	kebab, camel          convert an identifier to in-progress or inProgress
+//	trimPrefix            remove a prefix, as in {{.Name | trimPrefix "Pill"}}
+//	trimSuffix            remove a suffix, as in {{.Name | trimSuffix "Type"}}
+//	quote                 quote a string as a Go string literal
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"lower":          transforms["lower"],
+		"upper":          transforms["upper"],
+		"snake":          transforms["snake"],
+		"screamingSnake": transforms["screaming-snake"],
+		"kebab":          transforms["kebab"],
+		"camel":          transforms["camel"],
+		"trimPrefix":     func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix":     func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"quote":          strconv.Quote,
+	}
+}
+
+// ParseTemplate parses text as a template for Options.Template. The template
+// can call the functions returned by Funcs as well as those in funcs, which
+// take precedence and may be nil.
+func ParseTemplate(name, text string, funcs template.FuncMap) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs()).Funcs(funcs).Parse(text)
+}
+
+// contains reports whether s is an element of list.
+func contains(list []string, s string) bool {
+	for _, t := range list {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
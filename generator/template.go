@@ -0,0 +1,1900 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Added as a .go file to avoid embedding issues of the template.
+
+package generator
+
+import "text/template"
+
+var generatedTmpl = template.Must(template.New("generated").Parse(`
+// generated by jsonenums {{.Command}}; DO NOT EDIT
+
+{{if .JSONv2}}
+//go:build go1.27
+{{else if .All}}
+//go:build go1.23
+{{end}}
+
+package {{.PackageName}}
+
+{{if .GoGenerate}}
+//go:generate jsonenums {{.GoGenerate}}
+{{end}}
+
+import (
+    {{- if .Pgx}}
+    "context"
+    {{- end}}
+    {{- $sort := false}}{{range .Types}}{{if and (eq .Lookup "array") (not .HasString)}}{{$sort = true}}{{end}}{{end}}
+    {{- if $sort}}
+    "sort"
+    {{- end}}
+    {{- if or .Random .Quick}}
+    "math/rand"
+    {{- end}}
+    {{- if or .Quick .Jsoniter}}
+    "reflect"
+    {{- end}}
+    {{- $translations := false}}{{range .Types}}{{if .Translations}}{{$translations = true}}{{end}}{{end}}
+    {{- if or .Flag $translations .Env .Cobra .Kong}}
+    "strings"
+    {{- end}}
+    {{- if .SQL}}
+    "database/sql/driver"
+    {{- end}}
+    {{- if eq .Binary "int"}}
+    "encoding/binary"
+    {{- end}}
+    {{- if .Gob}}
+    "encoding/gob"
+    {{- end}}
+    "encoding/json"
+    {{- if .CLI}}
+    "flag"
+    {{- end}}
+    {{- if .JSONv2}}
+    "encoding/json/jsontext"
+    {{- end}}
+    {{- if .XML}}
+    "encoding/xml"
+    {{- end}}
+    "fmt"
+    {{- if .GQL}}
+    "io"
+    {{- end}}
+    {{- if .All}}
+    "iter"
+    {{- end}}
+    {{- $ints := false}}{{range .Types}}{{if not .IsString}}{{$ints = true}}{{end}}{{end}}
+    {{- if or .GQL (and .Gorm $ints)}}
+    "strconv"
+    {{- end}}
+    {{- if .Jsoniter}}
+    "unsafe"
+    {{- end}}
+    {{- if .Registry}}
+    "github.com/davars/jsonenums/enumreg"
+    {{- end}}
+    {{- if .CBOR}}
+    "github.com/fxamacker/cbor/v2"
+    {{- end}}
+    {{- if .Jsoniter}}
+    jsoniter "github.com/json-iterator/go"
+    {{- end}}
+    {{- if .Cobra}}
+    "github.com/spf13/cobra"
+    {{- end}}
+    {{- if .Kong}}
+    "github.com/alecthomas/kong"
+    {{- end}}
+    {{- if .CLI}}
+    "github.com/urfave/cli/v2"
+    {{- end}}
+    {{- if .EasyJSON}}
+    "github.com/mailru/easyjson/jlexer"
+    "github.com/mailru/easyjson/jwriter"
+    {{- end}}
+    {{- if .MsgPack}}
+    "github.com/vmihailenco/msgpack/v5"
+    {{- end}}
+    {{- if .Pgx}}
+    "github.com/jackc/pgx/v5"
+    "github.com/jackc/pgx/v5/pgtype"
+    {{- end}}
+    {{- if .BSON}}
+    "go.mongodb.org/mongo-driver/v2/bson"
+    {{- end}}
+    {{- if .YAML}}
+    "gopkg.in/yaml.v3"
+    {{- end}}
+)
+
+{{range .Types}}
+{{$typename := .Name}}{{$values := .Values}}{{$verb := "%d"}}{{if .IsString}}{{$verb = "%q"}}{{else if .IsFloat}}{{$verb = "%g"}}{{end}}
+{{$order := "in declaration order"}}{{if eq $.Order "value"}}{{$order = "sorted by value"}}{{else if eq $.Order "name"}}{{$order = "sorted by name"}}{{end}}
+{{$ptr := eq $.Receiver "pointer"}}{{$recv := printf "r %s" $typename}}{{if $ptr}}{{$recv = printf "p *%s" $typename}}{{end}}
+
+{{if or (eq .Lookup "map") .HasString}}
+var (
+    _{{$typename}}NameToValue = map[string]{{$typename}} {
+        {{range $v := $values}}{{if not .Shadowed}}{{printf "%q" .Name}}: {{.OriginalName}},
+        {{end}}{{range .Accept}}{{printf "%q" .}}: {{$v.OriginalName}},
+        {{end}}{{end}}
+    }
+    {{- if eq .Lookup "map"}}
+
+    _{{$typename}}ValueToName = map[{{$typename}}]string {
+        {{range $values}}{{if .Canonical}}{{.OriginalName}}: {{printf "%q" .Name}},
+        {{end}}{{end}}
+    }
+    {{- end}}
+)
+{{end}}
+
+{{if $.Bounds}}
+const (
+    {{- if not .IsString}}
+    // {{printf (index $.Bounds 0) $typename}} and {{printf (index $.Bounds 1) $typename}} are the lowest and highest values of {{$typename}}.
+    {{printf (index $.Bounds 0) $typename}} = {{.Min}}
+    {{printf (index $.Bounds 1) $typename}} = {{.Max}}
+    {{- end}}
+    // {{printf (index $.Bounds 2) $typename}} is the number of distinct values of {{$typename}}.
+    {{printf (index $.Bounds 2) $typename}} = {{.Count}}
+)
+{{end}}
+
+{{if eq .Lookup "array"}}
+var (
+    {{- if .ArrayBase}}
+    // _{{$typename}}Names holds the names, indexed by value minus {{.ArrayBase}}.
+    _{{$typename}}Names = [...]string{
+        {{range .ArrayValues}}{{printf "%q" .Name}}, // {{.OriginalName}}
+        {{end}}
+    }
+    {{- else}}
+    // _{{$typename}}Names holds the names, indexed by value.
+    _{{$typename}}Names = [...]string{
+        {{range .CanonicalValues}}{{.OriginalName}}: {{printf "%q" .Name}},
+        {{end}}
+    }
+    {{- end}}
+    {{- if not .HasString}}
+
+    // _{{$typename}}SortedNames holds the names in sorted order, and
+    // _{{$typename}}SortedValues the corresponding values.
+    _{{$typename}}SortedNames = [...]string{
+        {{range .SortedValues}}{{printf "%q" .Name}},
+        {{end}}
+    }
+    _{{$typename}}SortedValues = [...]{{$typename}}{
+        {{range .SortedValues}}{{.OriginalName}},
+        {{end}}
+    }
+    {{- end}}
+)
+{{end}}
+
+{{if eq .Lookup "array"}}
+// _{{$typename}}Index returns the index of v in the arrays indexed by value, if
+// it has one.
+func _{{$typename}}Index(v {{$typename}}) (uint64, bool) {
+    {{- if not .ArrayBase}}
+    i := uint64(v)
+    {{- else if .IsUnsigned}}
+    i := uint64(v) - uint64({{.ArrayBase}})
+    {{- else}}
+    // The difference is computed in 64 bits, where it cannot overflow for
+    // the values in the array, and wraps around to large indexes for those
+    // below {{.ArrayBase}}.
+    i := uint64(int64(v) - int64({{.ArrayBase}}))
+    {{- end}}
+    return i, i < uint64(len(_{{$typename}}Names))
+}
+{{end}}
+
+// _{{$typename}}Name returns the name of v, if it has one.
+func _{{$typename}}Name(v {{$typename}}) (string, bool) {
+    {{- if eq .Lookup "switch"}}
+    switch v {
+    {{- range $values}}{{if .Canonical}}
+    case {{.OriginalName}}:
+        return {{printf "%q" .Name}}, true
+    {{- end}}{{end}}
+    }
+    return "", false
+    {{- else if eq .Lookup "array"}}
+    i, ok := _{{$typename}}Index(v)
+    if !ok {
+        return "", false
+    }
+    return _{{$typename}}Names[i], true
+    {{- else}}
+    s, ok := _{{$typename}}ValueToName[v]
+    return s, ok
+    {{- end}}
+}
+
+// _{{$typename}}Value returns the value with name s, if any.
+func _{{$typename}}Value(s string) ({{$typename}}, bool) {
+    {{- if and (eq .Lookup "switch") (not .HasString)}}
+    switch s {
+    {{- range $values}}{{if or (not .Shadowed) .Accept}}
+    case {{if not .Shadowed}}{{printf "%q" .Name}}{{if .Accept}}, {{end}}{{end}}{{range $i, $a := .Accept}}{{if $i}}, {{end}}{{printf "%q" $a}}{{end}}:
+        return {{.OriginalName}}, true
+    {{- end}}{{end}}
+    }
+    var v {{$typename}}
+    return v, false
+    {{- else if and (eq .Lookup "array") (not .HasString)}}
+    i := sort.SearchStrings(_{{$typename}}SortedNames[:], s)
+    if i == len(_{{$typename}}SortedNames) || _{{$typename}}SortedNames[i] != s {
+        var v {{$typename}}
+        return v, false
+    }
+    return _{{$typename}}SortedValues[i], true
+    {{- else}}
+    v, ok := _{{$typename}}NameToValue[s]
+    return v, ok
+    {{- end}}
+}
+
+{{if .HasString}}
+func init() {
+    var v {{$typename}}
+    if _, ok := interface{}(v).(fmt.Stringer); ok {
+        _{{$typename}}NameToValue = map[string]{{$typename}} {
+            {{range $v := $values}}interface{}({{.OriginalName}}).(fmt.Stringer).String(): {{.OriginalName}},
+            {{range .Accept}}{{printf "%q" .}}: {{$v.OriginalName}},
+            {{end}}{{end}}
+        }
+    }
+}
+{{end}}
+
+{{if $.BitFlags}}
+// MarshalJSON is generated so {{$typename}} satisfies json.Marshaler.
+// The value is marshaled as the list of the names of its flags.
+func ({{$recv}}) MarshalJSON() ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    names := []string{}
+    rest := r
+    for _, v := range []{{$typename}}{ {{range $values}}{{if .Canonical}}{{.OriginalName}}, {{end}}{{end}} } {
+        if v != 0 && rest&v == v {
+            s, _ := _{{$typename}}Name(v)
+            names = append(names, s)
+            rest &^= v
+        }
+    }
+    if rest != 0 {
+        return nil, fmt.Errorf("invalid {{$typename}}: %d", r)
+    }
+    return json.Marshal(names)
+}
+
+// UnmarshalJSON is generated so {{$typename}} satisfies json.Unmarshaler.
+// The value is unmarshaled from a list of names of flags.
+func (r *{{$typename}}) UnmarshalJSON(data []byte) error {
+    {{- template "null" .}}
+    var names []string
+    if err := json.Unmarshal(data, &names); err != nil {
+        return fmt.Errorf("{{$typename}} should be a list of strings, got %s", data)
+    }
+    var v {{$typename}}
+    for _, s := range names {
+        f, ok := _{{$typename}}Value(s)
+        if !ok {
+            return fmt.Errorf("invalid {{$typename}} %q", s)
+        }
+        v |= f
+    }
+    *r = v
+    return nil
+}
+{{else}}
+{{if eq $.Marshal "number"}}
+// MarshalJSON is generated so {{$typename}} satisfies json.Marshaler.
+// The value is marshaled as a number.
+func ({{$recv}}) MarshalJSON() ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    if _, ok := _{{$typename}}Name(r); !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return []byte(fmt.Sprintf("{{$verb}}", r)), nil
+}
+{{else}}
+{{if eq .Lookup "switch"}}
+// _{{$typename}}JSON holds the marshaled names, in the order of the cases of
+// MarshalJSON, so that it does not allocate.
+var _{{$typename}}JSON = [...][]byte{
+    {{range .CanonicalValues}}[]byte({{.MarshaledName}}),
+    {{end}}
+}
+{{else if eq .Lookup "array"}}
+// _{{$typename}}JSON holds the marshaled names, indexed as _{{$typename}}Names, so
+// that MarshalJSON does not allocate.
+var _{{$typename}}JSON = [...][]byte{
+    {{- if .ArrayBase}}
+    {{- range .ArrayValues}}
+    []byte({{.MarshaledName}}), // {{.OriginalName}}
+    {{- end}}
+    {{- else}}
+    {{- range .CanonicalValues}}
+    {{.OriginalName}}: []byte({{.MarshaledName}}),
+    {{- end}}
+    {{- end}}
+}
+{{else}}
+// _{{$typename}}ValueToJSON holds the marshaled names, so that MarshalJSON does
+// not allocate.
+var _{{$typename}}ValueToJSON = func() map[{{$typename}}][]byte {
+    m := make(map[{{$typename}}][]byte, len(_{{$typename}}ValueToName))
+    for v, s := range _{{$typename}}ValueToName {
+        m[v], _ = json.Marshal(s)
+    }
+    return m
+}()
+{{end}}
+
+// MarshalJSON is generated so {{$typename}} satisfies json.Marshaler.
+// The returned slice is shared and must not be modified.
+func ({{$recv}}) MarshalJSON() ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return json.Marshal(s.String())
+    }
+    {{- end}}
+    {{- if eq .Lookup "switch"}}
+    switch r {
+    {{- range $i, $v := .CanonicalValues}}
+    case {{.OriginalName}}:
+        return _{{$typename}}JSON[{{$i}}], nil
+    {{- end}}
+    }
+    return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    {{- else if eq .Lookup "array"}}
+    i, ok := _{{$typename}}Index(r)
+    if !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return _{{$typename}}JSON[i], nil
+    {{- else}}
+    data, ok := _{{$typename}}ValueToJSON[r]
+    if !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return data, nil
+    {{- end}}
+}
+{{end}}
+
+// UnmarshalJSON is generated so {{$typename}} satisfies json.Unmarshaler.
+func (r *{{$typename}}) UnmarshalJSON(data []byte) error {
+    {{- template "null" .}}
+    var s string
+    if err := json.Unmarshal(data, &s); err != nil {
+        {{- if $.AcceptNumbers}}
+        {{- $num := "int64"}}{{if .IsUnsigned}}{{$num = "uint64"}}{{else if .IsFloat}}{{$num = "float64"}}{{end}}
+        var n {{$num}}
+        if json.Unmarshal(data, &n) == nil {
+            v := {{$typename}}(n)
+            {{- if .IsFloat}}
+            // Like encoding/json, numbers are rounded to the precision of
+            // the type.
+            if _, ok := _{{$typename}}Name(v); !ok {
+            {{- else}}
+            if _, ok := _{{$typename}}Name(v); !ok || {{$num}}(v) != n {
+            {{- end}}
+                return fmt.Errorf("invalid {{$typename}}: %s", data)
+            }
+            *r = v
+            return nil
+        }
+        return fmt.Errorf("{{$typename}} should be a string or a number, got %s", data)
+        {{- else}}
+        return fmt.Errorf("{{$typename}} should be a string, got %s", data)
+        {{- end}}
+    }
+    {{- template "decode" .}}
+}
+{{end}}
+
+{{if $.Append}}
+// AppendJSON appends {{$typename}} to dst as MarshalJSON marshals it, so that
+// encoders building their own buffers avoid allocating one for it.
+func ({{$recv}}) AppendJSON(dst []byte) ([]byte, error) {
+    data, err := {{if $ptr}}p{{else}}r{{end}}.MarshalJSON()
+    if err != nil {
+        return dst, err
+    }
+    return append(dst, data...), nil
+}
+
+// AppendText is generated so {{$typename}} satisfies encoding.TextAppender,
+// appending its name to dst.
+func ({{$recv}}) AppendText(dst []byte) ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return append(dst, s.String()...), nil
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return dst, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return append(dst, s...), nil
+}
+{{end}}
+
+{{if $.JSONv2}}
+// MarshalJSONTo is generated so {{$typename}} satisfies json.MarshalerTo of
+// encoding/json/v2, writing the value as MarshalJSON does.
+func ({{$recv}}) MarshalJSONTo(enc *jsontext.Encoder) error {
+    data, err := {{if $ptr}}p{{else}}r{{end}}.MarshalJSON()
+    if err != nil {
+        return err
+    }
+    return enc.WriteValue(data)
+}
+
+// UnmarshalJSONFrom is generated so {{$typename}} satisfies
+// json.UnmarshalerFrom of encoding/json/v2, reading the value as UnmarshalJSON
+// does.
+func (r *{{$typename}}) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+    data, err := dec.ReadValue()
+    if err != nil {
+        return err
+    }
+    return r.UnmarshalJSON(data)
+}
+{{end}}
+
+{{if $.Text}}
+// MarshalText is generated so {{$typename}} satisfies encoding.TextMarshaler.
+func ({{$recv}}) MarshalText() ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return []byte(s.String()), nil
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return []byte(s), nil
+}
+
+// UnmarshalText is generated so {{$typename}} satisfies encoding.TextUnmarshaler.
+func (r *{{$typename}}) UnmarshalText(text []byte) error {
+    s := string(text)
+    {{- template "decode" .}}
+}
+{{end}}
+
+{{if $.Bind}}
+// UnmarshalParam is generated so {{$typename}} satisfies echo.BindUnmarshaler
+// and binding.BindUnmarshaler of gin, decoding query, path and form
+// parameters as UnmarshalText does.
+func (r *{{$typename}}) UnmarshalParam(param string) error {
+    return r.UnmarshalText([]byte(param))
+}
+{{end}}
+
+{{if $.Env}}
+// Decode is generated so *{{$typename}} satisfies envconfig.Decoder of
+// github.com/kelseyhightower/envconfig. Unlike UnmarshalText, it lists the
+// accepted names when given an unknown one.
+func (r *{{$typename}}) Decode(value string) error {
+    v, ok := _{{$typename}}Value(value)
+    if !ok {
+        var names []string
+        for _, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+            text, _ := v.MarshalText()
+            names = append(names, string(text))
+        }
+        return fmt.Errorf("invalid {{$typename}} %q; must be one of %s", value, strings.Join(names, ", "))
+    }
+    *r = v
+    return nil
+}
+{{end}}
+
+{{if eq $.Binary "name"}}
+// MarshalBinary is generated so {{$typename}} satisfies encoding.BinaryMarshaler.
+func ({{$recv}}) MarshalBinary() ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return []byte(s.String()), nil
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return []byte(s), nil
+}
+
+// UnmarshalBinary is generated so {{$typename}} satisfies encoding.BinaryUnmarshaler.
+func (r *{{$typename}}) UnmarshalBinary(data []byte) error {
+    s := string(data)
+    {{- template "decode" .}}
+}
+{{else if eq $.Binary "int"}}
+// MarshalBinary is generated so {{$typename}} satisfies encoding.BinaryMarshaler.
+// The value is encoded as an 8-byte big-endian integer.
+func ({{$recv}}) MarshalBinary() ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    if _, ok := _{{$typename}}Name(r); !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    data := make([]byte, 8)
+    binary.BigEndian.PutUint64(data, uint64(r))
+    return data, nil
+}
+
+// UnmarshalBinary is generated so {{$typename}} satisfies encoding.BinaryUnmarshaler.
+func (r *{{$typename}}) UnmarshalBinary(data []byte) error {
+    if len(data) != 8 {
+        return fmt.Errorf("{{$typename}} should be 8 bytes, got %d", len(data))
+    }
+    v := {{$typename}}(binary.BigEndian.Uint64(data))
+    if _, ok := _{{$typename}}Name(v); !ok {
+        return fmt.Errorf("invalid {{$typename}}: %d", v)
+    }
+    *r = v
+    return nil
+}
+{{end}}
+
+{{if $.Gob}}
+// GobEncode is generated so {{$typename}} satisfies gob.GobEncoder.
+func ({{$recv}}) GobEncode() ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return []byte(s.String()), nil
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return []byte(s), nil
+}
+
+// GobDecode is generated so {{$typename}} satisfies gob.GobDecoder.
+func (r *{{$typename}}) GobDecode(data []byte) error {
+    s := string(data)
+    {{- template "decode" .}}
+}
+
+// Register{{$typename}}Gob registers {{$typename}} with encoding/gob so it can
+// be sent as the dynamic value of an interface.
+func Register{{$typename}}Gob() {
+    var v {{$typename}}
+    gob.Register(v)
+}
+{{end}}
+
+{{if $.EasyJSON}}
+// MarshalEasyJSON is generated so {{$typename}} satisfies easyjson.Marshaler,
+// writing the value as MarshalJSON does.
+func ({{$recv}}) MarshalEasyJSON(w *jwriter.Writer) {
+    w.Raw({{if $ptr}}p{{else}}r{{end}}.MarshalJSON())
+}
+
+// UnmarshalEasyJSON is generated so {{$typename}} satisfies
+// easyjson.Unmarshaler, reading the value as UnmarshalJSON does.
+func (r *{{$typename}}) UnmarshalEasyJSON(l *jlexer.Lexer) {
+    data := l.Raw()
+    if !l.Ok() {
+        return
+    }
+    if err := r.UnmarshalJSON(data); err != nil {
+        l.AddError(err)
+    }
+}
+{{end}}
+
+{{if $.Jsoniter}}
+// Register{{$typename}}Jsoniter registers with github.com/json-iterator/go
+// functions encoding and decoding {{$typename}} as MarshalJSON and
+// UnmarshalJSON do, which it then calls directly rather than through the
+// json.Marshaler and json.Unmarshaler interfaces.
+func Register{{$typename}}Jsoniter() {
+    typ := reflect.TypeOf((*{{$typename}})(nil)).Elem().String()
+    jsoniter.RegisterTypeEncoderFunc(typ, func(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+        data, err := (*{{$typename}})(ptr).MarshalJSON()
+        if err != nil {
+            stream.Error = err
+            return
+        }
+        stream.Write(data)
+    }, nil)
+    jsoniter.RegisterTypeDecoderFunc(typ, func(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+        data := iter.SkipAndReturnBytes()
+        if iter.Error != nil {
+            return
+        }
+        if err := (*{{$typename}})(ptr).UnmarshalJSON(data); err != nil {
+            iter.ReportError("decode {{$typename}}", err.Error())
+        }
+    })
+}
+{{end}}
+
+{{if $.YAML}}
+// MarshalYAML is generated so {{$typename}} satisfies yaml.Marshaler.
+func ({{$recv}}) MarshalYAML() (interface{}, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return s.String(), nil
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return s, nil
+}
+
+// UnmarshalYAML is generated so {{$typename}} satisfies yaml.Unmarshaler.
+func (r *{{$typename}}) UnmarshalYAML(value *yaml.Node) error {
+    var s string
+    if err := value.Decode(&s); err != nil {
+        return fmt.Errorf("{{$typename}} should be a string: %v", err)
+    }
+    {{- template "decode" .}}
+}
+{{end}}
+
+{{if $.XML}}
+// MarshalXML is generated so {{$typename}} satisfies xml.Marshaler.
+func ({{$recv}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return e.EncodeElement(s.String(), start)
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return e.EncodeElement(s, start)
+}
+
+// UnmarshalXML is generated so {{$typename}} satisfies xml.Unmarshaler.
+func (r *{{$typename}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+    var s string
+    if err := d.DecodeElement(&s, &start); err != nil {
+        return err
+    }
+    {{- template "decode" .}}
+}
+
+// MarshalXMLAttr is generated so {{$typename}} satisfies xml.MarshalerAttr.
+func ({{$recv}}) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return xml.Attr{Name: name, Value: s.String()}, nil
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return xml.Attr{}, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return xml.Attr{Name: name, Value: s}, nil
+}
+
+// UnmarshalXMLAttr is generated so {{$typename}} satisfies xml.UnmarshalerAttr.
+func (r *{{$typename}}) UnmarshalXMLAttr(attr xml.Attr) error {
+    s := attr.Value
+    {{- template "decode" .}}
+}
+{{end}}
+
+{{if $.GQL}}
+// MarshalGQL is generated so {{$typename}} satisfies graphql.Marshaler of
+// gqlgen. Values with no name are written as null.
+func ({{$recv}}) MarshalGQL(w io.Writer) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        io.WriteString(w, "null")
+        return
+    }
+    {{- if .HasString}}
+    if str, ok := interface{}(r).(fmt.Stringer); ok {
+        s = str.String()
+    }
+    {{- end}}
+    io.WriteString(w, strconv.Quote(s))
+}
+
+// UnmarshalGQL is generated so {{$typename}} satisfies graphql.Unmarshaler of
+// gqlgen.
+func (r *{{$typename}}) UnmarshalGQL(src interface{}) error {
+    s, ok := src.(string)
+    if !ok {
+        return fmt.Errorf("{{$typename}} should be a string, got %T", src)
+    }
+    {{- template "decode" .}}
+}
+{{end}}
+
+{{if $.MsgPack}}
+// EncodeMsgpack is generated so {{$typename}} satisfies msgpack.CustomEncoder.
+func ({{$recv}}) EncodeMsgpack(enc *msgpack.Encoder) error {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return enc.EncodeString(s.String())
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return enc.EncodeString(s)
+}
+
+// DecodeMsgpack is generated so {{$typename}} satisfies msgpack.CustomDecoder.
+func (r *{{$typename}}) DecodeMsgpack(dec *msgpack.Decoder) error {
+    s, err := dec.DecodeString()
+    if err != nil {
+        return fmt.Errorf("{{$typename}} should be a string: %v", err)
+    }
+    {{- template "decode" .}}
+}
+{{end}}
+
+{{if $.CBOR}}
+// MarshalCBOR is generated so {{$typename}} satisfies cbor.Marshaler.
+func ({{$recv}}) MarshalCBOR() ([]byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return cbor.Marshal(s.String())
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return cbor.Marshal(s)
+}
+
+// UnmarshalCBOR is generated so {{$typename}} satisfies cbor.Unmarshaler.
+func (r *{{$typename}}) UnmarshalCBOR(data []byte) error {
+    var s string
+    if err := cbor.Unmarshal(data, &s); err != nil {
+        return fmt.Errorf("{{$typename}} should be a string: %v", err)
+    }
+    {{- template "decode" .}}
+}
+{{end}}
+
+{{if $.BSON}}
+// MarshalBSONValue is generated so {{$typename}} satisfies bson.ValueMarshaler.
+func ({{$recv}}) MarshalBSONValue() (byte, []byte, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        t, data, err := bson.MarshalValue(s.String())
+        return byte(t), data, err
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return 0, nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    t, data, err := bson.MarshalValue(s)
+    return byte(t), data, err
+}
+
+// UnmarshalBSONValue is generated so {{$typename}} satisfies bson.ValueUnmarshaler.
+func (r *{{$typename}}) UnmarshalBSONValue(t byte, data []byte) error {
+    s, ok := bson.RawValue{Type: bson.Type(t), Value: data}.StringValueOK()
+    if !ok {
+        return fmt.Errorf("{{$typename}} should be a string, got %v", bson.Type(t))
+    }
+    {{- template "decode" .}}
+}
+{{end}}
+
+{{if $.SQL}}
+// Value is generated so {{$typename}} satisfies driver.Valuer.
+func ({{$recv}}) Value() (driver.Value, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return s.String(), nil
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return nil, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return s, nil
+}
+
+// Scan is generated so *{{$typename}} satisfies sql.Scanner.
+func (r *{{$typename}}) Scan(src interface{}) error {
+    switch src := src.(type) {
+    case string:
+        v, ok := _{{$typename}}Value(src)
+        if !ok {
+            {{- if and $.Gorm (not .IsString)}}
+            // Legacy numeric columns may be scanned as text.
+            {{- if .IsFloat}}
+            if n, err := strconv.ParseFloat(src, 64); err == nil {
+                return r.Scan(n)
+            }
+            {{- else if .IsUnsigned}}
+            if n, err := strconv.ParseUint(src, 10, 64); err == nil {
+                return r.Scan(int64(n))
+            }
+            {{- else}}
+            if n, err := strconv.ParseInt(src, 10, 64); err == nil {
+                return r.Scan(n)
+            }
+            {{- end}}
+            {{- end}}
+            return fmt.Errorf("invalid {{$typename}} %q", src)
+        }
+        *r = v
+    case []byte:
+        return r.Scan(string(src))
+    {{- if .IsFloat}}
+    case float64:
+        v := {{$typename}}(src)
+        if _, ok := _{{$typename}}Name(v); !ok || float64(v) != src {
+            return fmt.Errorf("invalid {{$typename}}: %g", src)
+        }
+        *r = v
+    {{- else if not .IsString}}
+    case int64:
+        v := {{$typename}}(src)
+        if _, ok := _{{$typename}}Name(v); !ok {
+            return fmt.Errorf("invalid {{$typename}}: %d", src)
+        }
+        *r = v
+    {{- end}}
+    default:
+        return fmt.Errorf("cannot scan %T into {{$typename}}", src)
+    }
+    return nil
+}
+{{end}}
+
+{{if $.Pgx}}
+// TextValue is generated so {{$typename}} satisfies pgtype.TextValuer, through
+// which pgx encodes it as the label of a Postgres enum.
+func ({{$recv}}) TextValue() (pgtype.Text, error) {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return pgtype.Text{String: s.String(), Valid: true}, nil
+    }
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        return pgtype.Text{}, fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return pgtype.Text{String: s, Valid: true}, nil
+}
+
+// ScanText is generated so *{{$typename}} satisfies pgtype.TextScanner, through
+// which pgx scans the label of a Postgres enum.
+func (r *{{$typename}}) ScanText(text pgtype.Text) error {
+    if !text.Valid {
+        return fmt.Errorf("cannot scan NULL into {{$typename}}")
+    }
+    s := text.String
+    {{- template "decode" .}}
+}
+
+// Register{{$typename}}Pgx loads the Postgres enum type with the given name
+// through conn and registers it with the type map of conn, as the default type
+// of {{$typename}} values. It returns an error if the labels of the enum and
+// the names of the constants of {{$typename}} differ.
+func Register{{$typename}}Pgx(ctx context.Context, conn *pgx.Conn, name string) error {
+    t, err := conn.LoadType(ctx, name)
+    if err != nil {
+        return err
+    }
+    rows, err := conn.Query(ctx, "SELECT enumlabel FROM pg_enum WHERE enumtypid = $1::regtype", name)
+    if err != nil {
+        return err
+    }
+    labels, err := pgx.CollectRows(rows, pgx.RowTo[string])
+    if err != nil {
+        return err
+    }
+    found := make(map[string]bool)
+    for _, l := range labels {
+        if _, ok := _{{$typename}}Value(l); !ok {
+            return fmt.Errorf("label %q of Postgres enum %s is not a name of {{$typename}}", l, name)
+        }
+        found[l] = true
+    }
+    names := []string{ {{range .CanonicalValues}}{{printf "%q" .Name}}, {{end}} }
+    {{- if .HasString}}
+    for i, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+        if s, ok := interface{}(v).(fmt.Stringer); ok {
+            names[i] = s.String()
+        }
+    }
+    {{- end}}
+    for _, s := range names {
+        if !found[s] {
+            return fmt.Errorf("{{$typename}} name %q is not a label of Postgres enum %s", s, name)
+        }
+    }
+    conn.TypeMap().RegisterType(t)
+    var v {{$typename}}
+    conn.TypeMap().RegisterDefaultPgType(v, name)
+    return nil
+}
+{{end}}
+
+{{if $.Gorm}}
+// GormDataType is generated so gorm creates columns holding the names of the
+// values of {{$typename}}.
+func ({{$typename}}) GormDataType() string {
+    return "string"
+}
+{{end}}
+
+{{if and (or $.Flag $.Ent) (not .HasString)}}
+// String is generated so {{$typename}} satisfies fmt.Stringer.
+func ({{$recv}}) String() string {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    s, ok := _{{$typename}}Name(r)
+    if !ok {
+        {{- if .IsString}}
+        return fmt.Sprintf("{{$typename}}(%q)", string(r))
+        {{- else}}
+        return fmt.Sprintf("{{$typename}}({{$verb}})", r)
+        {{- end}}
+    }
+    return s
+}
+{{end}}
+
+{{if $.Flag}}
+// Set is generated so *{{$typename}} satisfies flag.Value.
+func (r *{{$typename}}) Set(s string) error {
+    v, ok := _{{$typename}}Value(s)
+    if !ok {
+        var names []string
+        for _, v := range []{{$typename}}{ {{range $values}}{{if .Canonical}}{{.OriginalName}}, {{end}}{{end}} } {
+            names = append(names, v.String())
+        }
+        return fmt.Errorf("invalid {{$typename}} %q; must be one of %s", s, strings.Join(names, ", "))
+    }
+    *r = v
+    return nil
+}
+{{end}}
+
+{{if $.PFlag}}
+// Type is generated so *{{$typename}} satisfies pflag.Value, naming the type
+// of the flag in its usage.
+func ({{$typename}}) Type() string {
+    return {{printf "%q" $typename}}
+}
+
+// {{$typename}}FlagHelp returns a sentence listing the names accepted for
+// flags of type {{$typename}}, for their usage.
+func {{$typename}}FlagHelp() string {
+    var names []string
+    for _, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+        names = append(names, v.String())
+    }
+    return "one of " + strings.Join(names, ", ")
+}
+{{end}}
+
+{{if $.CLI}}
+// {{$typename}}Flag is a flag of github.com/urfave/cli/v2 holding a
+// {{$typename}}, set by name from the command line or from the first of its
+// EnvVars that is set.
+type {{$typename}}Flag struct {
+    Name        string
+    Aliases     []string
+    Usage       string
+    EnvVars     []string
+    Required    bool
+    Hidden      bool
+    Value       {{$typename}}  // The default value.
+    Destination *{{$typename}} // Where the value is stored, if not nil.
+
+    generic *cli.GenericFlag // The flag applied, once it is.
+}
+
+// genericFlag returns the cli.GenericFlag doing the work of f.
+func (f *{{$typename}}Flag) genericFlag() *cli.GenericFlag {
+    if f.generic == nil {
+        dest := f.Destination
+        if dest == nil {
+            dest = new({{$typename}})
+        }
+        *dest = f.Value
+        f.generic = &cli.GenericFlag{
+            Name:        f.Name,
+            Aliases:     f.Aliases,
+            Usage:       f.Usage,
+            EnvVars:     f.EnvVars,
+            Required:    f.Required,
+            Hidden:      f.Hidden,
+            Value:       dest,
+            DefaultText: f.Value.String(),
+        }
+    }
+    return f.generic
+}
+
+// Get returns the value of the flag in ctx.
+func (f *{{$typename}}Flag) Get(ctx *cli.Context) {{$typename}} {
+    if v, ok := ctx.Generic(f.Name).(*{{$typename}}); ok {
+        return *v
+    }
+    return f.Value
+}
+
+// Apply is generated so *{{$typename}}Flag satisfies cli.Flag.
+func (f *{{$typename}}Flag) Apply(set *flag.FlagSet) error { return f.genericFlag().Apply(set) }
+
+// String is generated so *{{$typename}}Flag satisfies cli.Flag.
+func (f *{{$typename}}Flag) String() string { return cli.FlagStringer(f) }
+
+// Names is generated so *{{$typename}}Flag satisfies cli.Flag.
+func (f *{{$typename}}Flag) Names() []string { return cli.FlagNames(f.Name, f.Aliases) }
+
+// IsSet is generated so *{{$typename}}Flag satisfies cli.Flag.
+func (f *{{$typename}}Flag) IsSet() bool { return f.genericFlag().IsSet() }
+
+// IsRequired is generated so *{{$typename}}Flag satisfies cli.RequiredFlag.
+func (f *{{$typename}}Flag) IsRequired() bool { return f.Required }
+
+// IsVisible is generated so *{{$typename}}Flag satisfies cli.VisibleFlag.
+func (f *{{$typename}}Flag) IsVisible() bool { return !f.Hidden }
+
+// TakesValue is generated so *{{$typename}}Flag satisfies cli.DocGenerationFlag.
+func (f *{{$typename}}Flag) TakesValue() bool { return true }
+
+// GetUsage is generated so *{{$typename}}Flag satisfies cli.DocGenerationFlag.
+// It lists the accepted names after the usage.
+func (f *{{$typename}}Flag) GetUsage() string {
+    var names []string
+    for _, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+        names = append(names, v.String())
+    }
+    usage := "one of " + strings.Join(names, ", ")
+    if f.Usage != "" {
+        usage = f.Usage + ", " + usage
+    }
+    return usage
+}
+
+// GetValue is generated so *{{$typename}}Flag satisfies cli.DocGenerationFlag.
+func (f *{{$typename}}Flag) GetValue() string { return f.Value.String() }
+
+// GetDefaultText is generated so *{{$typename}}Flag satisfies cli.DocGenerationFlag.
+func (f *{{$typename}}Flag) GetDefaultText() string { return f.Value.String() }
+
+// GetEnvVars is generated so *{{$typename}}Flag satisfies cli.DocGenerationFlag.
+func (f *{{$typename}}Flag) GetEnvVars() []string { return f.EnvVars }
+{{end}}
+
+{{if $.Kong}}
+// Decode is generated so *{{$typename}} satisfies kong.MapperValue of
+// github.com/alecthomas/kong, decoding flags and arguments by name and listing
+// the accepted names when given an unknown one.
+func (r *{{$typename}}) Decode(ctx *kong.DecodeContext) error {
+    var s string
+    if err := ctx.Scan.PopValueInto("{{$typename}}", &s); err != nil {
+        return err
+    }
+    v, ok := _{{$typename}}Value(s)
+    if !ok {
+        var names []string
+        for _, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+            {{- if .HasString}}
+            name := v.String()
+            {{- else}}
+            name, _ := _{{$typename}}Name(v)
+            {{- end}}
+            names = append(names, name)
+        }
+        return fmt.Errorf("invalid {{$typename}} %q; must be one of %s", s, strings.Join(names, ", "))
+    }
+    *r = v
+    return nil
+}
+{{end}}
+
+{{if $.Cobra}}
+// {{$typename}}Completion completes the names of {{$typename}} starting with
+// toComplete, for use as the ValidArgsFunction of a cobra.Command or with
+// RegisterFlagCompletionFunc.
+func {{$typename}}Completion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+    var names []string
+    for _, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+        {{- if .HasString}}
+        s := v.String()
+        {{- else}}
+        s, _ := _{{$typename}}Name(v)
+        {{- end}}
+        if strings.HasPrefix(s, toComplete) {
+            names = append(names, s)
+        }
+    }
+    return names, cobra.ShellCompDirectiveNoFileComp
+}
+{{end}}
+
+{{if $.Ent}}
+// Values is generated so {{$typename}} satisfies field.EnumValues of ent and
+// can be used with field.Enum(...).GoType. It returns the names ent accepts.
+func ({{$typename}}) Values() []string {
+    {{- if .HasString}}
+    var names []string
+    for _, v := range []{{$typename}}{ {{range $values}}{{if .Canonical}}{{.OriginalName}}, {{end}}{{end}} } {
+        names = append(names, v.String())
+    }
+    return names
+    {{- else}}
+    return []string{
+        {{range $values}}{{if .Canonical}}{{printf "%q" .Name}},
+        {{end}}{{end}}
+    }
+    {{- end}}
+}
+
+// Validate returns an error if r is not one of the values defined for
+// {{$typename}}, for use as a validator or hook of ent.
+func ({{$recv}}) Validate() error {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    if _, ok := _{{$typename}}Name(r); !ok {
+        return fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return nil
+}
+{{end}}
+
+{{if $.GoString}}
+// GoString is generated so {{$typename}} satisfies fmt.GoStringer, and the %#v
+// verb prints the name of the constant holding the value.
+func ({{$recv}}) GoString() string {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    switch r {
+    {{- range $values}}{{if .Canonical}}
+    case {{.OriginalName}}:
+        return "{{$.PackageName}}.{{.OriginalName}}"
+    {{- end}}{{end}}
+    }
+    return fmt.Sprintf("{{$.PackageName}}.{{$typename}}({{$verb}})", {{if .IsString}}string(r){{else}}r{{end}})
+}
+{{end}}
+
+{{if $.EnumSet}}
+{{- if le (len .CanonicalValues) 64}}
+// {{$typename}}Set is a set of {{$typename}} values, held as a bit set.
+type {{$typename}}Set uint64
+
+var _{{$typename}}SetBits = map[{{$typename}}]{{$typename}}Set{
+    {{range $i, $v := .CanonicalValues}}{{$v.OriginalName}}: 1 << {{$i}},
+    {{end}}
+}
+
+// Add adds the values to s. Values not defined for {{$typename}} are ignored.
+func (s *{{$typename}}Set) Add(values ...{{$typename}}) {
+    for _, v := range values {
+        *s |= _{{$typename}}SetBits[v]
+    }
+}
+
+// Remove removes the values from s.
+func (s *{{$typename}}Set) Remove(values ...{{$typename}}) {
+    for _, v := range values {
+        *s &^= _{{$typename}}SetBits[v]
+    }
+}
+
+// Contains reports whether s holds v.
+func (s {{$typename}}Set) Contains(v {{$typename}}) bool {
+    b, ok := _{{$typename}}SetBits[v]
+    return ok && s&b != 0
+}
+{{- else}}
+// {{$typename}}Set is a set of {{$typename}} values.
+type {{$typename}}Set map[{{$typename}}]struct{}
+
+// Add adds the values to s, allocating it if nil. Values not defined for
+// {{$typename}} are ignored.
+func (s *{{$typename}}Set) Add(values ...{{$typename}}) {
+    if *s == nil {
+        *s = make({{$typename}}Set)
+    }
+    for _, v := range values {
+        if _, ok := _{{$typename}}Name(v); ok {
+            (*s)[v] = struct{}{}
+        }
+    }
+}
+
+// Remove removes the values from s.
+func (s *{{$typename}}Set) Remove(values ...{{$typename}}) {
+    for _, v := range values {
+        delete(*s, v)
+    }
+}
+
+// Contains reports whether s holds v.
+func (s {{$typename}}Set) Contains(v {{$typename}}) bool {
+    _, ok := s[v]
+    return ok
+}
+{{- end}}
+
+// Values returns the values held by s, {{$order}}.
+func (s {{$typename}}Set) Values() []{{$typename}} {
+    var values []{{$typename}}
+    for _, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+        if s.Contains(v) {
+            values = append(values, v)
+        }
+    }
+    return values
+}
+
+// MarshalJSON is generated so {{$typename}}Set satisfies json.Marshaler.
+// The set is marshaled as the list of the names of its values.
+func (s {{$typename}}Set) MarshalJSON() ([]byte, error) {
+    names := []string{}
+    for _, v := range s.Values() {
+        {{- if .HasString}}
+        if s, ok := interface{}(v).(fmt.Stringer); ok {
+            names = append(names, s.String())
+            continue
+        }
+        {{- end}}
+        s, _ := _{{$typename}}Name(v)
+        names = append(names, s)
+    }
+    return json.Marshal(names)
+}
+
+// UnmarshalJSON is generated so {{$typename}}Set satisfies json.Unmarshaler.
+func (s *{{$typename}}Set) UnmarshalJSON(data []byte) error {
+    var names []string
+    if err := json.Unmarshal(data, &names); err != nil {
+        return fmt.Errorf("{{$typename}}Set should be a list of names, got %s", data)
+    }
+    var set {{$typename}}Set
+    for _, name := range names {
+        v, ok := _{{$typename}}Value(name)
+        if !ok {
+            return fmt.Errorf("invalid {{$typename}} %q", name)
+        }
+        set.Add(v)
+    }
+    *s = set
+    return nil
+}
+{{end}}
+
+{{if $.Values}}
+// {{$typename}}Values returns the distinct values defined for {{$typename}}, {{$order}}.
+func {{$typename}}Values() []{{$typename}} {
+    return []{{$typename}}{
+        {{range .CanonicalValues}}{{.OriginalName}},
+        {{end}}
+    }
+}
+
+// {{$typename}}Names returns the names of the distinct values defined for
+// {{$typename}}, {{$order}}.
+func {{$typename}}Names() []string {
+    {{- if .HasString}}
+    values := {{$typename}}Values()
+    names := make([]string, len(values))
+    for i, v := range values {
+        names[i] = v.String()
+    }
+    return names
+    {{- else}}
+    return []string{
+        {{range .CanonicalValues}}{{printf "%q" .Name}},
+        {{end}}
+    }
+    {{- end}}
+}
+{{end}}
+
+{{if $.All}}
+// {{$typename}}All returns an iterator over the distinct values defined for
+// {{$typename}}, {{$order}}.
+func {{$typename}}All() iter.Seq[{{$typename}}] {
+    return func(yield func({{$typename}}) bool) {
+        for _, v := range [...]{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+            if !yield(v) {
+                return
+            }
+        }
+    }
+}
+{{end}}
+
+{{if $.Description}}
+var _{{$typename}}ValueToDescription = map[{{$typename}}]string {
+    {{range $values}}{{if not .Alias}}{{.OriginalName}}: {{printf "%q" .Description}},
+    {{end}}{{end}}
+}
+
+// Description returns the doc comment of the first constant declared equal
+// to r, or the empty string if there is none.
+func ({{$recv}}) Description() string {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    return _{{$typename}}ValueToDescription[r]
+}
+{{end}}
+
+{{if $.IsDeprecated}}
+// IsDeprecated reports whether r is only defined by deprecated constants of
+// {{$typename}}.
+func ({{$recv}}) IsDeprecated() bool {
+    {{- if .Deprecated}}
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    switch r {
+    case {{range $i, $c := .Deprecated}}{{if $i}}, {{end}}{{$c}}{{end}}:
+        return true
+    }
+    {{- end}}
+    return false
+}
+{{end}}
+
+{{if $.IsValid}}
+// IsValid reports whether r is one of the values defined for {{$typename}}.
+func ({{$recv}}) IsValid() bool {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    _, ok := _{{$typename}}Name(r)
+    return ok
+}
+{{end}}
+
+{{if $.Parse}}
+// Parse{{$typename}} returns the {{$typename}} constant with the given name.
+func Parse{{$typename}}(s string) ({{$typename}}, error) {
+    v, ok := _{{$typename}}Value(s)
+    if !ok {
+        return v, fmt.Errorf("invalid {{$typename}} %q", s)
+    }
+    return v, nil
+}
+
+// MustParse{{$typename}} is like Parse{{$typename}} but panics if the name is invalid.
+func MustParse{{$typename}}(s string) {{$typename}} {
+    v, err := Parse{{$typename}}(s)
+    if err != nil {
+        panic(err)
+    }
+    return v
+}
+{{end}}
+
+{{if $.Random}}
+// Random{{$typename}} returns one of the values defined for {{$typename}}, picked
+// uniformly using r.
+func Random{{$typename}}(r *rand.Rand) {{$typename}} {
+    values := []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} }
+    return values[r.Intn(len(values))]
+}
+{{end}}
+
+{{if $.DisplayNames}}
+{{- if .Translations}}
+// _{{$typename}}DisplayNames holds the display names of the constants, by language.
+var _{{$typename}}DisplayNames = map[string]map[{{$typename}}]string{
+    {{- $canonical := .CanonicalValues}}
+    {{- range $t := .Translations}}
+    {{printf "%q" $t.Lang}}: {
+        {{- range $canonical}}{{$label := index $t.Labels .OriginalName}}{{if $label}}
+        {{.OriginalName}}: {{printf "%q" $label}},{{end}}{{end}}
+    },
+    {{- end}}
+}
+{{end}}
+
+// DisplayName returns the name of {{$typename}} displayed to users in the
+// language lang, such as "fr" or "fr-CA", falling back to its base language
+// "fr", then to the name it is marshaled as.
+func ({{$recv}}) DisplayName(lang string) string {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    {{- if .Translations}}
+    for lang != "" {
+        if s, ok := _{{$typename}}DisplayNames[lang][r]; ok {
+            return s
+        }
+        i := strings.LastIndexAny(lang, "-_")
+        if i < 0 {
+            break
+        }
+        lang = lang[:i]
+    }
+    {{- end}}
+    {{- if .HasString}}
+    if s, ok := interface{}(r).(fmt.Stringer); ok {
+        return s.String()
+    }
+    {{- end}}
+    s, _ := _{{$typename}}Name(r)
+    return s
+}
+{{end}}
+
+{{if $.Switch}}
+// Switch calls the function handling the value of {{$typename}}, out of one
+// for each of its constants {{$order}}, or returns an error if it
+// has none. As adding a constant adds a parameter, calls fail to compile until
+// they handle it.
+func ({{$recv}}) Switch({{range $i, $v := .CanonicalValues}}{{if $i}}, {{end}}on{{$v.OriginalName}}{{end}} func()) error {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    switch r {
+    {{- range .CanonicalValues}}
+    case {{.OriginalName}}:
+        on{{.OriginalName}}()
+    {{- end}}
+    default:
+        return fmt.Errorf("invalid {{$typename}}: {{$verb}}", r)
+    }
+    return nil
+}
+{{end}}
+
+{{if $.Registry}}
+func init() {
+    names := []string{ {{range .CanonicalValues}}{{printf "%q" .Name}}, {{end}} }
+    {{- if .HasString}}
+    for i, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+        if s, ok := interface{}(v).(fmt.Stringer); ok {
+            names[i] = s.String()
+        }
+    }
+    {{- end}}
+    enumreg.Register(enumreg.Enum{
+        Package: {{printf "%q" $.PackagePath}},
+        Name:    {{printf "%q" $typename}},
+        Names:   names,
+        Parse: func(name string) (interface{}, bool) {
+            v, ok := _{{$typename}}Value(name)
+            return v, ok
+        },
+    })
+}
+{{end}}
+
+{{if $.Cycle}}
+// _{{$typename}}Cycle holds the constants {{$order}}, as Next and Prev go
+// through them.
+var _{{$typename}}Cycle = [...]{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} }
+
+// Next returns the constant following {{$typename}} {{$order}}, or the first
+// one after the last, so that repeated calls cycle through all of them. A
+// value with no constant is returned as it is.
+func ({{$recv}}) Next() {{$typename}} {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    for i, v := range _{{$typename}}Cycle {
+        if v == r {
+            return _{{$typename}}Cycle[(i+1)%len(_{{$typename}}Cycle)]
+        }
+    }
+    return r
+}
+
+// Prev returns the constant preceding {{$typename}} {{$order}}, or the last
+// one before the first, undoing Next. A value with no constant is returned as
+// it is.
+func ({{$recv}}) Prev() {{$typename}} {
+    {{- if $ptr}}
+    r := *p
+    {{- end}}
+    for i, v := range _{{$typename}}Cycle {
+        if v == r {
+            return _{{$typename}}Cycle[(i+len(_{{$typename}}Cycle)-1)%len(_{{$typename}}Cycle)]
+        }
+    }
+    return r
+}
+{{end}}
+
+{{if $.Quick}}
+// Generate is generated so {{$typename}} satisfies quick.Generator, and
+// quick.Check only produces the values defined for {{$typename}}.
+func ({{$typename}}) Generate(r *rand.Rand, size int) reflect.Value {
+    values := []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} }
+    return reflect.ValueOf(values[r.Intn(len(values))])
+}
+{{end}}
+
+{{if $.Slices}}
+// Parse{{$typename}}Slice returns the {{$typename}} constants with the given names.
+func Parse{{$typename}}Slice(names []string) ([]{{$typename}}, error) {
+    values := make([]{{$typename}}, len(names))
+    for i, s := range names {
+        v, ok := _{{$typename}}Value(s)
+        if !ok {
+            return nil, fmt.Errorf("invalid {{$typename}} %q at index %d", s, i)
+        }
+        values[i] = v
+    }
+    return values, nil
+}
+
+// {{$typename}}SliceStrings returns the names of the values. Values with no
+// name are formatted as conversions, as in {{$typename}}({{if .IsString}}"x"{{else}}1{{end}}).
+func {{$typename}}SliceStrings(values []{{$typename}}) []string {
+    names := make([]string, len(values))
+    for i, v := range values {
+        s, ok := _{{$typename}}Name(v)
+        if !ok {
+            s = fmt.Sprintf("{{$typename}}({{$verb}})", {{if .IsString}}string(v){{else}}v{{end}})
+        }
+        {{- if .HasString}} else if str, ok := interface{}(v).(fmt.Stringer); ok {
+            s = str.String()
+        }
+        {{- end}}
+        names[i] = s
+    }
+    return names
+}
+{{end}}
+
+{{end}}
+
+{{define "null"}}
+    {{- if ne .Null "error"}}
+    if string(data) == "null" {
+        {{- if eq .Null "zero"}}
+        var v {{.Name}}
+        *r = v
+        {{- else if eq .Null "default"}}
+        *r = {{.Default}}
+        {{- end}}
+        return nil
+    }
+    {{- end}}
+{{- end}}
+
+{{define "decode"}}
+    {{- if eq .Unknown "zero"}}
+    // Unknown names decode as the zero value.
+    *r, _ = _{{.Name}}Value(s)
+    {{- else}}
+    v, ok := _{{.Name}}Value(s)
+    if !ok {
+        {{- if eq .Unknown "default"}}
+        v = {{.Default}}
+        {{- else if eq .Unknown "keep"}}
+        return nil
+        {{- else}}
+        return fmt.Errorf("invalid {{.Name}} %q", s)
+        {{- end}}
+    }
+    *r = v
+    {{- end}}
+    return nil
+{{- end}}
+`))
+
+var testTmpl = template.Must(template.New("test").Parse(`
+// generated by jsonenums {{.Command}}; DO NOT EDIT
+
+{{if .Fuzz}}
+//go:build go1.18
+// +build go1.18
+{{end}}
+
+package {{.PackageName}}
+
+import (
+    "encoding/json"
+    {{- if and .Tests .XML}}
+    "encoding/xml"
+    {{- end}}
+    "testing"
+)
+
+{{range .Types}}
+{{$typename := .Name}}
+
+{{if $.Tests}}
+func Test{{$typename}}JSONRoundTrip(t *testing.T) {
+    for _, v := range []{{$typename}}{ {{range .Values}}{{if .Canonical}}{{.OriginalName}}, {{end}}{{end}} } {
+        data, err := json.Marshal(&v)
+        if err != nil {
+            t.Errorf("marshaling %v: %v", v, err)
+            continue
+        }
+        var got {{$typename}}
+        if err := json.Unmarshal(data, &got); err != nil {
+            t.Errorf("unmarshaling %s: %v", data, err)
+            continue
+        }
+        if got != v {
+            t.Errorf("unmarshaling %s: got %v, want %v", data, got, v)
+        }
+        again, err := json.Marshal(&got)
+        if err != nil {
+            t.Errorf("marshaling %v: %v", got, err)
+            continue
+        }
+        if string(again) != string(data) {
+            t.Errorf("marshaling %v: got %s, want %s", got, again, data)
+        }
+    }
+}
+
+func Test{{$typename}}JSONUnknown(t *testing.T) {
+    {{- $first := (index .Values 0).OriginalName}}
+    got := {{$first}}
+    {{- if $.BitFlags}}
+    err := json.Unmarshal([]byte({{printf "%q" (printf "[%q]" .UnknownName)}}), &got)
+    {{- else}}
+    err := json.Unmarshal([]byte({{printf "%q" (printf "%q" .UnknownName)}}), &got)
+    {{- end}}
+    {{- if or (eq .Unknown "error") $.BitFlags}}
+    if err == nil {
+        t.Errorf("unmarshaling an unknown name: got %v, want an error", got)
+    }
+    {{- else}}
+    if err != nil {
+        t.Fatalf("unmarshaling an unknown name: %v", err)
+    }
+    {{- if eq .Unknown "zero"}}
+    var want {{$typename}}
+    {{- else if eq .Unknown "default"}}
+    want := {{.Default}}
+    {{- else}}
+    want := {{$first}}
+    {{- end}}
+    if got != want {
+        t.Errorf("unmarshaling an unknown name: got %v, want %v", got, want)
+    }
+    {{- end}}
+}
+
+{{if $.MapKeys}}
+func Test{{$typename}}JSONMapKeys(t *testing.T) {
+    want := map[{{$typename}}]int{ {{range $i, $v := .CanonicalValues}}{{$v.OriginalName}}: {{$i}}, {{end}} }
+    data, err := json.Marshal(want)
+    if err != nil {
+        t.Fatalf("marshaling %v: %v", want, err)
+    }
+    var got map[{{$typename}}]int
+    if err := json.Unmarshal(data, &got); err != nil {
+        t.Fatalf("unmarshaling %s: %v", data, err)
+    }
+    if len(got) != len(want) {
+        t.Errorf("unmarshaling %s: got %v, want %v", data, got, want)
+    }
+    for k, v := range want {
+        if got[k] != v {
+            t.Errorf("unmarshaling %s: got %v, want %v", data, got, want)
+            break
+        }
+    }
+}
+{{end}}
+
+{{if $.XML}}
+func Test{{$typename}}XMLRoundTrip(t *testing.T) {
+    type element struct {
+        Attr {{$typename}} ` + "`xml:\"attr,attr\"`" + `
+        Elem {{$typename}} ` + "`xml:\"elem\"`" + `
+    }
+    for _, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+        data, err := xml.Marshal(element{v, v})
+        if err != nil {
+            t.Errorf("marshaling %v: %v", v, err)
+            continue
+        }
+        var got element
+        if err := xml.Unmarshal(data, &got); err != nil {
+            t.Errorf("unmarshaling %s: %v", data, err)
+            continue
+        }
+        if got.Attr != v || got.Elem != v {
+            t.Errorf("unmarshaling %s: got %v and %v, want %v", data, got.Attr, got.Elem, v)
+        }
+    }
+}
+{{end}}
+{{end}}
+
+{{if $.Benchmarks}}
+func Benchmark{{$typename}}MarshalJSON(b *testing.B) {
+    values := []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} }
+    b.ReportAllocs()
+    for i := 0; i < b.N; i++ {
+        if _, err := json.Marshal(&values[i%len(values)]); err != nil {
+            b.Fatal(err)
+        }
+    }
+}
+
+func Benchmark{{$typename}}UnmarshalJSON(b *testing.B) {
+    var data [][]byte
+    for _, v := range []{{$typename}}{ {{range .CanonicalValues}}{{.OriginalName}}, {{end}} } {
+        d, err := json.Marshal(&v)
+        if err != nil {
+            b.Fatal(err)
+        }
+        data = append(data, d)
+    }
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        var v {{$typename}}
+        if err := json.Unmarshal(data[i%len(data)], &v); err != nil {
+            b.Fatal(err)
+        }
+    }
+}
+{{end}}
+
+{{if $.Fuzz}}
+func Fuzz{{$typename}}UnmarshalJSON(f *testing.F) {
+    for _, v := range []{{$typename}}{ {{range .Values}}{{.OriginalName}}, {{end}} } {
+        if data, err := json.Marshal(&v); err == nil {
+            f.Add(data)
+        }
+    }
+    f.Add([]byte({{printf "%q" (printf "%q" .UnknownName)}}))
+    f.Fuzz(func(t *testing.T, data []byte) {
+        var v {{$typename}}
+        if err := json.Unmarshal(data, &v); err != nil {
+            return
+        }
+        out, err := json.Marshal(&v)
+        if err != nil {
+            // Unknown names may be unmarshaled as a value with no name.
+            return
+        }
+        var again {{$typename}}
+        if err := json.Unmarshal(out, &again); err != nil {
+            t.Fatalf("unmarshaling %s, marshaled from %s: %v", out, data, err)
+        }
+        if again != v {
+            t.Fatalf("unmarshaling %s, marshaled from %s: got %v, want %v", out, data, again, v)
+        }
+    })
+}
+{{end}}
+{{end}}
+`))
+
+var tomlTestTmpl = template.Must(template.New("tomltest").Parse(`
+// generated by jsonenums {{.Command}}; DO NOT EDIT
+
+package {{.PackageName}}
+
+import (
+    "bytes"
+    "testing"
+
+    burntsushi "github.com/BurntSushi/toml"
+    gotoml "github.com/pelletier/go-toml/v2"
+)
+
+{{range .Types}}
+{{$typename := .Name}}
+func Test{{$typename}}BurntSushiTOMLRoundTrip(t *testing.T) {
+    for _, v := range []{{$typename}}{ {{range .Values}}{{if .Canonical}}{{.OriginalName}}, {{end}}{{end}} } {
+        var buf bytes.Buffer
+        if err := burntsushi.NewEncoder(&buf).Encode(struct{ V {{$typename}} }{v}); err != nil {
+            t.Errorf("encoding %v: %v", v, err)
+            continue
+        }
+        var got struct{ V {{$typename}} }
+        if _, err := burntsushi.Decode(buf.String(), &got); err != nil {
+            t.Errorf("decoding %s: %v", buf.Bytes(), err)
+            continue
+        }
+        if got.V != v {
+            t.Errorf("decoding %s: got %v, want %v", buf.Bytes(), got.V, v)
+        }
+    }
+}
+
+func Test{{$typename}}GoTOMLRoundTrip(t *testing.T) {
+    for _, v := range []{{$typename}}{ {{range .Values}}{{if .Canonical}}{{.OriginalName}}, {{end}}{{end}} } {
+        data, err := gotoml.Marshal(struct{ V {{$typename}} }{v})
+        if err != nil {
+            t.Errorf("marshaling %v: %v", v, err)
+            continue
+        }
+        var got struct{ V {{$typename}} }
+        if err := gotoml.Unmarshal(data, &got); err != nil {
+            t.Errorf("unmarshaling %s: %v", data, err)
+            continue
+        }
+        if got.V != v {
+            t.Errorf("unmarshaling %s: got %v, want %v", data, got.V, v)
+        }
+    }
+}
+{{end}}
+`))
@@ -0,0 +1,88 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// transforms maps the accepted values of Options.Transform to the function
+// applied to every constant name.
+var transforms = map[string]func(string) string{
+	"none":            func(s string) string { return s },
+	"upper":           strings.ToUpper,
+	"lower":           strings.ToLower,
+	"snake":           func(s string) string { return strings.ToLower(strings.Join(splitWords(s), "_")) },
+	"screaming-snake": ScreamingSnake,
+	"kebab":           func(s string) string { return strings.ToLower(strings.Join(splitWords(s), "-")) },
+	"camel":           camel,
+}
+
+// TransformNames returns the sorted list of valid values of Options.Transform.
+func TransformNames() []string {
+	var names []string
+	for name := range transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ScreamingSnake converts s to upper case words separated by underscores:
+// InProgress becomes IN_PROGRESS.
+func ScreamingSnake(s string) string {
+	return strings.ToUpper(strings.Join(splitWords(s), "_"))
+}
+
+// camel converts s to lower camel case: InProgress becomes inProgress and
+// HTTPServer becomes httpServer.
+func camel(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if i > 0 && w != "" {
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			w = string(r)
+		}
+		words[i] = w
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits a Go identifier into the words it is made of. Words are
+// separated by underscores or by a change of case, keeping runs of upper case
+// letters such as acronyms together: HTTPServer is split into HTTP and Server.
+func splitWords(s string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' }) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, cur := runes[i-1], runes[i]
+			if !unicode.IsUpper(cur) {
+				continue
+			}
+			endOfAcronym := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || endOfAcronym {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -2,4 +2,7 @@ module github.com/davars/jsonenums
 
 go 1.12
 
-require golang.org/x/tools v0.0.0-20190523174634-38d8bcfa38af
+require (
+	golang.org/x/tools v0.0.0-20190523174634-38d8bcfa38af
+	gopkg.in/yaml.v3 v3.0.1
+)
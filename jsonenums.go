@@ -13,7 +13,7 @@
 
 // JSONenums is a tool to automate the creation of methods that satisfy the
 // fmt.Stringer, json.Marshaler and json.Unmarshaler interfaces.
-// Given the name of a (signed or unsigned) integer type T that has constants
+// Given the name of a (signed or unsigned) integer, float or string type T that has constants
 // defined, jsonenums will create a new self-contained Go source file implementing
 //
 //  func (t T) String() string
@@ -59,102 +59,1626 @@
 //
 //	//go:generate jsonenums -type=Pill
 //
-// If multiple constants have the same value, the lexically first matching name will
-// be used (in the example, Acetaminophen will print as "Paracetamol").
+// If multiple constants have the same value, the name of the first one declared will
+// be used (in the example, Acetaminophen will print as "Paracetamol"), unless
+// another one is marked with a //jsonenums:canonical line comment or line in its
+// doc comment. The names of all of them are accepted when unmarshaling.
+//
+// The constants of T are found by the type checker, so those declared with an
+// alias of T, as in type Status = status, or computed from other constants are
+// included, and -type may name either the type or one of its aliases.
 //
 // With no arguments, it processes the package in the current directory.
-// Otherwise, the arguments must name a single directory holding a Go package
-// or a set of Go source files that represent a single Go package.
+// Otherwise, each argument must name a single Go package, either by its
+// directory or by its import path, such as github.com/me/proj/api, resolved
+// from the current directory. Import paths let go:generate directives run
+// from any directory of the module. The generated file is written to the
+// directory of the package.
+//
+// The argument may also be a pattern such as ./... matching several packages,
+// in which case a file is generated in each of them for the types it defines,
+// so a whole module can be regenerated with a single command. The -output flag
+// cannot be used then. At the root of a workspace, where a go.work file lists
+// sibling modules, ./... matches the packages of all of them, and import paths
+// of any of them are resolved from each.
+//
+// Several packages or patterns may be given, each optionally followed by an
+// equal sign and the comma-separated types to generate for in its packages,
+// which otherwise are those of -type or, without it, the marked types. The
+// packages are parsed and generated concurrently, so a monorepo is
+// regenerated by a single command instead of one go:generate directive per
+// package:
+//
+//	jsonenums ./api=Status,Kind ./billing=Currency ./internal/...
+//
+// A package may only be matched by one of them.
 //
 // The -type flag accepts a comma-separated list of types so a single run can
 // generate methods for multiple types. The default output file is
 // t_jsonenums.go, where t is the lower-cased name of the first type listed.
-// The suffix can be overridden with the -suffix flag and a prefix may be added
-// with the -prefix flag.
+// The suffix can be overridden with the -suffix flag, as in -suffix=_gen for
+// repositories whose linters and coverage tools skip the files matching
+// *_gen.go, and a prefix may be added with the -prefix flag. Both are used as
+// given, and a trailing .go in the suffix is not repeated. Alternatively, the
+// -output flag sets the path of the generated file explicitly, relative to the
+// current directory; it may name a file in a different directory, but the
+// generated code still belongs to the package that defines the types.
+//
+// The -filename flag names the single file generated in the directory of each
+// package, such as enums_gen.go, holding the methods of all its types sorted
+// by name, so that the file does not depend on the order of -type or of the
+// declarations. Unlike -output, it can be used with patterns matching several
+// packages.
+//
+// The -split flag writes a file for each type instead, such as
+// status_jsonenums.go and kind_jsonenums.go, so that changing the constants of
+// one type only changes its own file. The -output flag cannot be used then.
+//
+// The -gogenerate flag adds to the generated file a directive such as
+//
+//	//go:generate jsonenums -type=Pill -transform=snake -gogenerate
+//
+// repeating the flags jsonenums was run with, so that go generate ./...
+// regenerates the file even when the directive that first generated it lives
+// in another package or in a script. When several files are generated for a
+// package, only the first of them in sorted order holds the directive, which
+// regenerates them all. The -type flag lists the types of the package, and
+// paths are relative to its directory. Once the files are generated, the
+// original directive can be removed.
+//
+// The header of the generated files records the arguments jsonenums was run
+// with, which may hold absolute paths or list the flags in any order. With
+// -reproducible, it records instead the canonical arguments of -gogenerate,
+// so that the generated files only depend on the flags set, the source of the
+// packages and the version of jsonenums, whichever machine, directory or
+// script runs it. This is useful for hermetic builds that hash the generated
+// sources, or for -check in continuous integration. The generated files hold
+// no timestamp, and their contents are always in a stable order.
+//
+// The -tags flag accepts a comma-separated list of build tags selecting the
+// files of the package that are parsed, so constants declared in files with
+// build constraints such as //go:build integration can be found.
+//
+// The -includetests flag makes jsonenums parse the _test.go files of the
+// package too, so types only used by tests and fixtures can be generated for.
+// The default output file is then t_jsonenums_test.go, so the generated code
+// is only compiled by go test, and the file written by -tests is
+// t_jsonenums_tests_test.go.
+//
+// Instead of listing the types with -type, they can be marked with a
+// //jsonenums:generate line in their doc comment,
+//
+//	//jsonenums:generate
+//	type Pill int
+//
+// and jsonenums run without -type generates for every marked type of the
+// package, or of every package matched by a pattern such as ./..., writing
+// the types of each package to a single file named after the first of them.
+//
+// Options for a single type can be given next to it, in a //jsonenums: line
+// comment on its declaration, which also marks it:
+//
+//	type Status int //jsonenums: transform=snake, trimprefix=Status, sql
+//
+// They are flags without their dash, with values after an equal sign that may
+// be left out for booleans, and apply on top of the flags given on the command
+// line. Only the flags about the generated methods may be given, not those
+// about files. Types with different options are generated to separate files,
+// each named after the first of its types.
+//
+// Constants that must not appear in the marshaled form, such as internal
+// sentinels, are left out when marked with //jsonenums:skip, either as their
+// line comment or on a line of their doc comment. Their names are then
+// rejected like any other unknown name.
+//
+// Other spellings of the name of a constant, such as those sent by older
+// clients, are accepted when unmarshaling if listed by a //jsonenums:accept
+// line comment or line in its doc comment,
+//
+//	InProgress //jsonenums:accept "in-progress", "IN_PROGRESS"
+//
+// while the constant is still marshaled under its own name. The spellings are
+// used verbatim, without applying -trimprefix or -transform.
+//
+// T may also be a string type, in which case each constant is represented by its
+// own value rather than by its name, and the generated methods only validate that
+// values being marshaled or unmarshaled are among the defined constants.
+//
+// T may also be a float type with a fixed set of constants, such as ratios.
+// They are marshaled by name like integers, and a name is only unmarshaled as
+// the exact value of its constant. Float types cannot be used with -bitflags
+// or -binary=int.
+//
+// The -trimprefix flag removes the given prefix from the constant names before
+// they are used in the marshaled form, so that with -trimprefix=Pill a constant
+// named PillAspirin is represented as "Aspirin".
+//
+// The -transform flag converts the constant names to a different case style
+// after any prefix has been trimmed. It accepts none (the default), upper,
+// lower, snake, screaming-snake, kebab and camel, so that InProgress is
+// represented as "in_progress" with -transform=snake or as "IN_PROGRESS" with
+// -transform=screaming-snake. The transformed names are used both when
+// marshaling and when unmarshaling. Constants with different values whose
+// names become the same, such as HTTPServer and HttpServer with
+// -transform=lower, are all reported and nothing is generated.
+//
+// The -linecomment flag makes the text of a single line comment on the same
+// line as a constant override the name used for that constant, so that
+//
+//	Aspirin // acetylsalicylic-acid
+//
+// is represented as "acetylsalicylic-acid". The comment text is used verbatim,
+// without applying -trimprefix or -transform.
+//
+// Even without -linecomment, a line comment written like a struct tag with a
+// json key gives the name of its constant, as in
+//
+//	StatusInProgress // json:"in_progress"
+//
+// overriding -trimprefix and -transform, while json:"-" leaves the constant
+// out like //jsonenums:skip.
+//
+// The -char flag is meant for rune and byte types whose constants are
+// characters. Constants whose values are written as rune literals, such as
+//
+//	Spades Suit = '♠'
+//
+// are represented by their characters, here "♠", while other constants keep
+// their names. Line comments still take precedence with -linecomment.
+//
+// The -numbers flag makes UnmarshalJSON accept, besides the names, a JSON
+// number equal to one of the constants, for clients still sending the integer
+// form. Numbers not matching any constant are always rejected. It cannot be
+// used for string types or with -bitflags.
+//
+// The -marshal flag chooses how MarshalJSON represents values: name, the
+// default, or number, which produces the integer value for compactness while
+// UnmarshalJSON still validates its input and accepts both names and numbers,
+// as with -numbers.
+//
+// The -bitflags flag is meant for integer types whose constants are bit flags,
+// as in
+//
+//	const (
+//		Read Perm = 1 << iota
+//		Write
+//		Exec
+//	)
+//
+// With it, MarshalJSON produces the list of the names of the flags set in the
+// value, so Read|Write is marshaled as ["Read","Write"], and UnmarshalJSON
+// combines the flags named in such a list, failing on unknown names. The
+// constants are tried in the order set by -order, so constants combining
+// several flags are used when they come first. Other methods still handle a single
+// constant at a time.
+//
+// The -unknown flag controls what the generated unmarshaling methods do with a
+// name that does not match any constant: error (the default) returns an error,
+// zero sets the receiver to the zero value, keep leaves the receiver unchanged,
+// and default sets it to the constant of the type listed in the comma-separated
+// -default flag.
+//
+// The -null flag similarly controls what UnmarshalJSON does with a JSON null,
+// which many APIs send for absent values, with the same choices. With error,
+// the default, null is rejected as the empty name would be; keep follows the
+// convention of encoding/json of treating null as a no-op.
+//
+// The methods that do not modify the value, such as MarshalJSON, are generated
+// with value receivers. The -receiver=pointer flag generates them with pointer
+// receivers instead, to share a method set with the other methods of types
+// declared with pointer receivers. Only pointers to T then satisfy
+// json.Marshaler, so encoding/json only uses MarshalJSON for addressable
+// values, such as the fields of a struct marshaled through a pointer.
+//
+// The constants appear in the generated code in declaration order, which follows
+// the files of the package and changes when constants move between files. The
+// -order flag sorts them instead by value or by name, keeping the generated
+// code stable however the package is laid out.
+//
+// The generated methods look names and values up with switch statements for
+// types with at most 16 distinct values, which the compiler turns into a few
+// comparisons. Larger types whose values are consecutive integers, as declared
+// with iota even when starting from a negative value, look names up by indexing
+// an array and values by binary search of a sorted array, and other types use
+// maps. The -lookup flag forces one of these with switch, array or map.
+//
+// The -header flag names a file, such as a license, whose contents are
+// prepended to the generated Go, protobuf and TypeScript files, with each line
+// turned into a // comment unless it already is one. JSON Schema files, which
+// cannot hold comments, are left as they are.
+//
+// The -template flag names a text/template file executed instead of the
+// built-in template to produce the generated file, so organization-specific
+// method sets can be generated without modifying jsonenums. The template
+// receives the PackageName and the Types, each with its Name and the list of
+// its Values, holding the OriginalName of every constant and the Name it is
+// marshaled as, along with the options set by the other flags. Besides the
+// functions predefined by text/template, it can call
+//
+//	lower, upper          change the case of a string
+//	snake, screamingSnake convert an identifier to in_progress or IN_PROGRESS
+//	kebab, camel          convert an identifier to in-progress or inProgress
+//	trimPrefix            remove a prefix, as in {{.Name | trimPrefix "Pill"}}
+//	trimSuffix            remove a suffix, as in {{.Name | trimSuffix "Type"}}
+//	quote                 quote a string as a Go string literal
+//
+// The -append flag additionally generates
+//
+//  func (t T) AppendJSON(dst []byte) ([]byte, error)
+//  func (t T) AppendText(dst []byte) ([]byte, error)
+//
+// appending t to dst as MarshalJSON and MarshalText represent it, so that
+// encoders building their own buffers do not allocate one for each value.
+// AppendText makes T satisfy encoding.TextAppender. The error is only returned
+// for values with no constant, leaving dst as it is.
+//
+// The -jsonv2 flag additionally generates
+//
+//  func (t T) MarshalJSONTo(*jsontext.Encoder) error
+//  func (t *T) UnmarshalJSONFrom(*jsontext.Decoder) error
+//
+// so encoding/json/v2 reads and writes the values straight from and to its
+// streams, as MarshalJSON and UnmarshalJSON represent them. The generated file
+// then needs Go 1.27, which provides encoding/json/jsontext, and is only built
+// by it and later versions through a //go:build go1.27 constraint.
+//
+// The -text flag additionally generates
+//
+//  func (t T) MarshalText() ([]byte, error)
+//  func (t *T) UnmarshalText([]byte) error
+//
+// so T satisfies encoding.TextMarshaler and encoding.TextUnmarshaler and can be
+// used with any encoder honoring those interfaces, or as a JSON map key.
+//
+// The -mapkeys flag generates the methods of -text, which encoding/json uses to
+// marshal the keys of maps such as map[T]int as names rather than numbers.
+// With -tests, the _test.go file also checks that such a map round-trips. As
+// encoding/json marshals the keys of string types as they are, -mapkeys
+// rejects string constants whose names differ from their values.
+//
+// The -toml flag generates the methods of -text, through which the TOML
+// libraries github.com/BurntSushi/toml and github.com/pelletier/go-toml/v2
+// decode T from its name. With -tests, a file with the name of the generated
+// file ending in _toml_test.go is also written, checking that the constants
+// round-trip through both libraries, which the package must then depend on.
+//
+// The -bind flag generates the methods of -text and additionally
+//
+//  func (t *T) UnmarshalParam(string) error
+//
+// through which the web frameworks github.com/labstack/echo/v4 and
+// github.com/gin-gonic/gin bind query, path and form parameters of type T,
+// rejecting unknown names as UnmarshalJSON does.
+//
+// The -env flag generates the methods of -text, through which
+// github.com/caarlos0/env decodes environment variables, and additionally
+//
+//  func (t *T) Decode(string) error
+//
+// through which github.com/kelseyhightower/envconfig does, reporting the
+// accepted names when given an unknown one.
+//
+// The -yaml flag additionally generates
+//
+//  func (t T) MarshalYAML() (interface{}, error)
+//  func (t *T) UnmarshalYAML(*yaml.Node) error
+//
+// so T satisfies the yaml.Marshaler and yaml.Unmarshaler interfaces of
+// gopkg.in/yaml.v3, which the package must then depend on.
+//
+// The -xml flag additionally generates
+//
+//  func (t T) MarshalXML(*xml.Encoder, xml.StartElement) error
+//  func (t *T) UnmarshalXML(*xml.Decoder, xml.StartElement) error
+//  func (t T) MarshalXMLAttr(xml.Name) (xml.Attr, error)
+//  func (t *T) UnmarshalXMLAttr(xml.Attr) error
+//
+// so T is encoded by name by encoding/xml, both as the character data of an
+// element and as the value of an attribute. With -tests, the _test.go file also
+// checks that every constant round-trips both ways.
+//
+// The -gql flag additionally generates
+//
+//  func (t T) MarshalGQL(io.Writer)
+//  func (t *T) UnmarshalGQL(interface{}) error
+//
+// so T can be bound directly to a GraphQL enum by github.com/99designs/gqlgen,
+// without a hand-written scalar. The names must then match the values of the
+// GraphQL enum, which -transform=screaming-snake produces by convention.
+//
+// The -msgpack flag additionally generates
+//
+//  func (t T) EncodeMsgpack(*msgpack.Encoder) error
+//  func (t *T) DecodeMsgpack(*msgpack.Decoder) error
+//
+// so T is encoded by name by github.com/vmihailenco/msgpack/v5, which the
+// package must then depend on.
+//
+// The -cbor flag additionally generates
+//
+//  func (t T) MarshalCBOR() ([]byte, error)
+//  func (t *T) UnmarshalCBOR([]byte) error
+//
+// so T is encoded as a CBOR text string holding its name by
+// github.com/fxamacker/cbor/v2, which the package must then depend on.
+//
+// The -bson flag additionally generates
+//
+//  func (t T) MarshalBSONValue() (byte, []byte, error)
+//  func (t *T) UnmarshalBSONValue(byte, []byte) error
+//
+// so T is stored in MongoDB documents by name by
+// go.mongodb.org/mongo-driver/v2, which the package must then depend on.
+// Unknown names are handled as with UnmarshalJSON.
+//
+// The -sql flag additionally generates
+//
+//  func (t T) Value() (driver.Value, error)
+//  func (t *T) Scan(interface{}) error
+//
+// so T satisfies driver.Valuer and sql.Scanner and is stored in databases by
+// name. Scan accepts a string or []byte holding a name and, for integer types,
+// an int64 holding one of the defined values.
+//
+// The -binary flag additionally generates
+//
+//  func (t T) MarshalBinary() ([]byte, error)
+//  func (t *T) UnmarshalBinary([]byte) error
+//
+// so T satisfies encoding.BinaryMarshaler and encoding.BinaryUnmarshaler, and is
+// stored by caches and other stores relying on those interfaces. With
+// -binary=name the value is encoded as its name, like MarshalText; with
+// -binary=int it is encoded as an 8-byte big-endian integer, which is more
+// compact but, unlike the name, does not survive renumbering the constants.
+//
+// The -gob flag additionally generates
+//
+//  func (t T) GobEncode() ([]byte, error)
+//  func (t *T) GobDecode([]byte) error
+//  func RegisterTGob()
+//
+// so T is sent through encoding/gob by name, and values keep their meaning when
+// the constants are renumbered between versions. RegisterTGob registers T with
+// gob.Register, needed to send T as the dynamic value of an interface, and is
+// left to the caller to invoke rather than run by an init function.
+//
+// The -easyjson flag additionally generates
+//
+//  func (t T) MarshalEasyJSON(*jwriter.Writer)
+//  func (t *T) UnmarshalEasyJSON(*jlexer.Lexer)
+//
+// so the code generated by github.com/mailru/easyjson for structs holding T
+// calls them directly instead of falling back to encoding/json.
+//
+// The -jsoniter flag additionally generates
+//
+//  func RegisterTJsoniter()
+//
+// registering with github.com/json-iterator/go functions that encode and
+// decode T as MarshalJSON and UnmarshalJSON do, calling them directly rather
+// than through interfaces. Like RegisterTGob, it is left to the caller to
+// invoke.
+//
+// The -flag flag additionally generates
+//
+//  func (t *T) Set(string) error
+//
+// and, unless T already declares one, a String method returning the name of the
+// value, so T satisfies flag.Value and can be used with flag.Var. Set reports the
+// accepted names when given an unknown one.
+//
+// The -pflag flag generates the methods of -flag and additionally
+//
+//  func (T) Type() string
+//  func TFlagHelp() string
+//
+// so T satisfies pflag.Value of github.com/spf13/pflag, used by Cobra, and
+// TFlagHelp returns a sentence listing the accepted names for the usage of the
+// flag, as in
+//
+//  cmd.Flags().Var(&status, "status", "status of the job, "+StatusFlagHelp())
+//
+// The -cli flag generates the methods of -flag and additionally a TFlag type,
+// a flag of github.com/urfave/cli/v2 holding a T, with the fields of the flags
+// of that package and a Get method returning its value:
+//
+//  flags := []cli.Flag{&StatusFlag{Name: "status", Value: Active, EnvVars: []string{"STATUS"}}}
+//
+// Its usage lists the accepted names, and its default value is shown by name.
+//
+// The -kong flag additionally generates
+//
+//  func (t *T) Decode(*kong.DecodeContext) error
+//
+// so T satisfies kong.MapperValue and github.com/alecthomas/kong decodes the
+// flags and arguments of type T by name, reporting the accepted names when
+// given an unknown one. It cannot be used with -env, whose Decode method has
+// another signature.
+//
+// The -cobra flag additionally generates
+//
+//  func TCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+//
+// completing the names of T for the flags and arguments of
+// github.com/spf13/cobra commands, as in
+//
+//  cmd.RegisterFlagCompletionFunc("status", StatusCompletion)
+//
+// The -pgx flag additionally generates
+//
+//  func (t T) TextValue() (pgtype.Text, error)
+//  func (t *T) ScanText(pgtype.Text) error
+//  func RegisterTPgx(ctx context.Context, conn *pgx.Conn, name string) error
+//
+// so github.com/jackc/pgx/v5, which the package must then depend on, stores T
+// as the labels of the Postgres enum type with the given name. RegisterTPgx
+// loads and registers that type with the type map of conn, and fails if its
+// labels differ from the names of the constants, catching a schema and code out
+// of sync at startup rather than on the first query.
+//
+// The -gorm flag additionally generates
+//
+//  func (T) GormDataType() string
+//
+// together with the methods of -sql, so fields of type T in gorm.io/gorm models
+// are stored by name in string columns. Scan then also accepts, for integer
+// types, integers formatted as text, so columns still holding the integer
+// values of the constants keep being read correctly.
+//
+// The -ent flag additionally generates
+//
+//  func (T) Values() []string
+//  func (t T) Validate() error
+//
+// together with the methods of -sql and, unless T already declares one, a String
+// method, so T can be used with field.Enum(...).GoType(T(0)) in the schemas of
+// entgo.io/ent and is stored by name. Values lists the names of the constants
+// and Validate, usable as a validator or hook, rejects any other value.
+//
+// The -gostring flag additionally generates
+//
+//  func (t T) GoString() string
+//
+// returning the name of the constant holding t qualified by the package name,
+// as in "pill.Aspirin", so the %#v verb, used in debugging dumps and the diffs
+// of failed tests, shows symbolic names rather than numbers.
+//
+// The -set flag additionally generates a TSet type holding a set of T values,
+// with the methods
+//
+//  func (s *TSet) Add(...T)
+//  func (s *TSet) Remove(...T)
+//  func (s TSet) Contains(T) bool
+//  func (s TSet) Values() []T
+//
+// and JSON methods marshaling it as the list of the names of its values. TSet is
+// a bit set if T has at most 64 distinct values, and a map otherwise.
+//
+// The -values flag additionally generates
+//
+//  func TValues() []T
+//  func TNames() []string
+//
+// returning each distinct value of T, leaving out aliases, and its name, in the
+// order set by -order.
+//
+// The -bounds flag additionally generates the constants
+//
+//	const (
+//		_TMin  = <the constant holding the lowest value>
+//		_TMax  = <the constant holding the highest value>
+//		TCount = <the number of distinct values>
+//	)
+//
+// so that range checks and arrays indexed by T stay in sync with its
+// constants. String types only get TCount. The -boundsnames flag sets the
+// names, as a comma-separated list of three formats with %s standing for the
+// type, by default _%sMin,_%sMax,%sCount.
+//
+// The -all flag additionally generates
+//
+//  func TAll() iter.Seq[T]
+//
+// iterating over the distinct values of T in the order set by -order, as in
+// for s := range StatusAll(). The generated file then needs Go 1.23, and is
+// only built by it and later versions through a //go:build go1.23 constraint.
+//
+// The -isvalid flag additionally generates
+//
+//  func (t T) IsValid() bool
+//
+// reporting whether t is one of the constants defined for T, which is useful
+// for values obtained through conversions rather than unmarshaling.
+//
+// The -description flag additionally generates
+//
+//	func (t T) Description() string
+//
+// returning the doc comment of the constant t is equal to, with its lines
+// joined, so documentation and user interfaces can show explanations
+// maintained next to the constants.
+//
+// Constants whose doc comment has a paragraph starting with "Deprecated: " are
+// deprecated. The -isdeprecated flag additionally generates
+//
+//	func (t T) IsDeprecated() bool
+//
+// reporting whether t is only defined by deprecated constants, and the
+// -acceptdeprecated flag makes the names of deprecated constants accepted when
+// unmarshaling but never produced when marshaling, easing the migration of a
+// wire format: a deprecated constant equal to another one is marshaled with
+// the name of the other one, while marshaling a value only defined by
+// deprecated constants fails.
+//
+// The -parse flag additionally generates
+//
+//  func ParseT(string) (T, error)
+//  func MustParseT(string) T
+//
+// mapping names to constants outside of any encoding, for instance when
+// reading command line arguments or environment variables.
+//
+// The -slices flag additionally generates
+//
+//  func ParseTSlice([]string) ([]T, error)
+//  func TSliceStrings([]T) []string
+//
+// converting between lists of names and of constants, as found in the fields of
+// request and response structs, without a loop at each call site.
+//
+// The -random flag additionally generates
+//
+//  func RandomT(*rand.Rand) T
+//
+// returning one of the values defined for T, picked uniformly, for property
+// based tests and test fixtures.
+//
+// The -switch flag additionally generates
+//
+//  func (t T) Switch(onA, onB, ... func()) error
+//
+// calling the function handling t, out of one for each constant, or returning
+// an error if t has no constant. Unlike a switch statement, whose missing cases
+// go unnoticed, calls to Switch fail to compile once a constant is added, until
+// they handle it.
+//
+// The -displaynames flag names a directory of files holding the names of the
+// constants displayed to users, one per language, such as fr.json or
+// fr-CA.json, each mapping type names to maps from constant names to display
+// names:
+//
+//  {"Pill": {"Aspirin": "Aspirine", "Ibuprofen": "Ibuprofène"}}
+//
+// It additionally generates
+//
+//  func (T) DisplayName(lang string) string
+//
+// returning the display name of a constant in lang, or in its base language if
+// lang has none, so "fr-CA" falls back to "fr", and otherwise the name it is
+// marshaled as. A display name given for an alias applies to the constant it
+// aliases.
+//
+// The -registry flag registers each type with the package
+// github.com/davars/jsonenums/enumreg when its package is initialized, so that
+// tools only knowing the names of the types, such as admin pages and form
+// builders, can list and parse their values:
+//
+//  v, err := enumreg.Parse("Status", "Active")
+//
+// The -cycle flag additionally generates
+//
+//  func (t T) Next() T
+//  func (t T) Prev() T
+//
+// returning the constant following or preceding t in the order set by -order,
+// such as -order=value for severity levels, and wrapping around from the last
+// constant to the first and back. Values with no constant are returned as they
+// are.
+//
+// The -quick flag additionally generates
+//
+//  func (T) Generate(*rand.Rand, int) reflect.Value
+//
+// so T satisfies quick.Generator and the functions of testing/quick only
+// produce the values defined for T.
+//
+// The -tests flag additionally writes, next to the generated file, a file with
+// the same name ending in _test.go that checks that every constant survives
+// being marshaled, unmarshaled and marshaled again, and that an unknown name
+// is unmarshaled as configured by -unknown, so that regressions are caught by
+// go test.
+//
+// The -fuzz flag adds to the same _test.go file a fuzz target
+//
+//	func FuzzTUnmarshalJSON(*testing.F)
+//
+// seeded with the marshaled constants, which checks that whatever
+// UnmarshalJSON accepts marshals back to the same value. Fuzzing requires Go
+// 1.18, so the file is then constrained to that version.
+//
+// The -benchmarks flag adds to the same _test.go file the benchmarks
+//
+//  func BenchmarkTMarshalJSON(*testing.B)
+//  func BenchmarkTUnmarshalJSON(*testing.B)
+//
+// going through encoding/json, so that the cost of the generated methods, or of
+// those of a -template, can be tracked with go test -bench.
+//
+// The -jsonschema flag additionally writes, next to the generated file, a file
+// t.schema.json for each type holding a JSON Schema fragment
+//
+//	{"type": "string", "enum": [...]}
+//
+// listing each name accepted by UnmarshalJSON once, including those given by
+// //jsonenums:accept, so schemas can be kept in sync with the Go code. With
+// -bitflags the schema is that of an array of such names, and with
+// -marshal=number it lists the values of the constants instead, as in
+// {"type": "integer", "enum": [0, 1, 2]}. Names returned by a String method
+// declared for the type are not known to jsonenums and therefore not reflected
+// in the schema.
+//
+// The -typescript flag additionally writes, next to the generated file, a file
+// t.ts for each type declaring
+//
+//	export type T = "A" | "B" | ...;
+//
+// listing the names accepted by UnmarshalJSON as -jsonschema does, so frontend
+// code shares the names of the constants. As with -jsonschema, names returned
+// by a String method declared for the type are not reflected.
+//
+// The -proto flag additionally writes, next to the generated file, a file
+// t.proto for each type holding a protobuf enum definition. The values are
+// named after the constants in screaming snake case, prefixed with the name of
+// the type unless -protoprefix=false is given. For integer types they are
+// numbered by their Go values, so that the numbers, which protobuf encodes,
+// do not change as constants are added or reordered. Other types give the
+// number of each distinct value with a //jsonenums:proto line comment or line
+// in the doc comment of one of its constants,
+//
+//	Pending Status = "pending" //jsonenums:proto 1
+//
+// which integer constants may also use to override their value. The values
+// are listed by number, and aliases share the number of the constant they
+// alias with option allow_alias set. Since proto3 requires the first value to
+// be numbered 0, the -protozero flag names an extra value, such as
+// UNSPECIFIED, numbered 0 ahead of the constants when none of them is.
+//
+// The -check flag makes jsonenums generate every file in memory and compare it
+// with the one on disk instead of writing it. If any of them is missing or
+// different, the first differing line of each is reported and jsonenums exits
+// with a non-zero status, which is useful in continuous integration. The -check
+// flag itself is not recorded in the header of the generated files.
+//
+// The -diff flag also generates every file in memory without writing it, and
+// prints the differences with the file on disk in the unified format, to
+// preview the effect of changing flags or templates. Combined with -check, the
+// differences are printed before jsonenums fails. Like -check, it is not
+// recorded in the header of the generated files.
+//
+// The -stdout flag writes the generated files to standard output instead of to
+// disk, for piping them into other tools or inspecting them. A single file is
+// written as it is, while several, such as those of -tests or of a pattern
+// matching several packages, are concatenated in the txtar format, each
+// preceded by a line
+//
+//	-- path/to/t_jsonenums.go --
+//
+// giving its path relative to the current directory. Like -check, it is not
+// recorded in the header of the generated files.
+//
+// The -watch flag keeps jsonenums running, regenerating the files each time a
+// Go file in the directories of the packages changes, for workflows that
+// reload as soon as the code changes. Each run is a separate jsonenums process
+// whose errors, such as those of a package that does not compile yet, are
+// reported without ending the watch.
+//
+// The -openapi flag names an OpenAPI document, in YAML or JSON, whose schemas
+// named after the types, under components/schemas or definitions, get their
+// enum lists rewritten in place with the names UnmarshalJSON accepts, as with
+// -jsonschema. With -openapicheck the document is not modified and jsonenums
+// fails if any of the lists is out of date, which is useful in continuous
+// integration.
+//
+// Settings shared by everyone generating a package can be kept in a file
+// named .jsonenums.yaml in its directory instead of growing the go:generate
+// lines. Its keys are the names of flags, lists being joined with commas, and
+// flags given on the command line take precedence. The templates key lists
+// extra text/template files executed like -template, each writing the file
+// named by its output next to the package:
+//
+//	type: [Pill, Color]
+//	transform: snake
+//	filename: enums_jsonenums.go
+//	templates:
+//	  - file: list.tmpl
+//	    output: enums_list.go
+//
+// The paths of templates, including the one of -template, and those of
+// -header and -openapi are relative to the configuration file.
+//
+// The file in the directory jsonenums is run from, or in that of the package
+// given as its only argument, applies to the whole run. The files of the other
+// packages matched, as by ./... or import paths, apply to their package only,
+// on top of it, and may only set type, prefix, suffix, filename, split and the
+// flags about the generated code, those that can also be given in the line
+// comment of a type.
+//
+// The jsonenumsvet command, in cmd/jsonenumsvet, runs an analyzer reporting
+// switch statements over the types generated for by jsonenums that miss some
+// of their constants, other than those marked with //jsonenums:skip, unless
+// they have a default case:
+//
+//	go install github.com/davars/jsonenums/cmd/jsonenumsvet
+//	go vet -vettool=$(which jsonenumsvet) ./...
+//
+// The analyzer itself is exhaustive.Analyzer, for use with other drivers.
+//
+// Programs that generate code of their own can produce the same methods
+// without running jsonenums by importing the generator package.
 //
 package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
-	"go/format"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"math/big"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/davars/jsonenums/generator"
 	"github.com/davars/jsonenums/parser"
 )
 
 var (
-	typeNames    = flag.String("type", "", "comma-separated list of type names; must be set")
+	typeNames    = flag.String("type", "", "comma-separated list of type names; default the types marked with "+parser.GenerateDirective)
 	outputPrefix = flag.String("prefix", "", "prefix to be added to the output file")
 	outputSuffix = flag.String("suffix", "_jsonenums", "suffix to be added to the output file")
+	output       = flag.String("output", "", "output file name; default srcdir/<prefix><type><suffix>.go")
+	buildTags    = flag.String("tags", "", "comma-separated list of build tags to apply")
+	filename     = flag.String("filename", "", "`name` of the single file generated in the directory of each package for all its types")
+	reproducible = flag.Bool("reproducible", false, "record canonical arguments, independent of the machine and the working directory, in the generated files")
+	goGenerate   = flag.Bool("gogenerate", false, "add a //go:generate directive repeating the flags to the generated file")
+	split        = flag.Bool("split", false, "write a file for each type instead of a single file for all of them")
+	includeTests = flag.Bool("includetests", false, "also parse _test.go files and write the output to a _test.go file")
+	headerFile   = flag.String("header", "", "`file` whose contents are prepended as comments to the generated files")
+	templateFile = flag.String("template", "", "text/template `file` executed instead of the built-in template")
+	trimPrefix   = flag.String("trimprefix", "", "trim the `prefix` from the generated constant names")
+	transform    = flag.String("transform", "none", "case style applied to the generated constant names; one of "+strings.Join(generator.TransformNames(), ", "))
+	lineComment  = flag.Bool("linecomment", false, "use line comment text as the marshaled name when present")
+	char         = flag.Bool("char", false, "use the characters of constants written as rune literals as their marshaled names")
+	order        = flag.String("order", "declaration", "order of the constants in the generated code; one of declaration, value, name")
+	lookup       = flag.String("lookup", "auto", "lookup of names and values in the generated methods; one of auto, switch, map, array")
+	receiver     = flag.String("receiver", "value", "receiver of the generated methods that do not modify the value; one of value, pointer")
+	marshal      = flag.String("marshal", "name", "representation of values produced by MarshalJSON; one of name, number")
+	numbers      = flag.Bool("numbers", false, "also accept the integer values of the constants in UnmarshalJSON")
+	bitFlags     = flag.Bool("bitflags", false, "marshal values to JSON as lists of the names of their bit flags")
+	text         = flag.Bool("text", false, "generate MarshalText and UnmarshalText methods")
+	unknown      = flag.String("unknown", "error", "handling of unknown names when unmarshaling; one of error, zero, default, keep")
+	null         = flag.String("null", "error", "handling of JSON null when unmarshaling; one of error, zero, default, keep")
+	defaults     = flag.String("default", "", "comma-separated list of constants used for unknown names or null with -unknown=default or -null=default")
+	enumSet      = flag.Bool("set", false, "generate a TSet type holding sets of values")
+	valuesFuncs  = flag.Bool("values", false, "generate TValues and TNames functions listing the constants")
+	all          = flag.Bool("all", false, "generate TAll functions returning an iter.Seq over the values, which need Go 1.23")
+	bounds       = flag.Bool("bounds", false, "generate constants holding the lowest and highest values of each type and its number of values")
+	boundsNames  = flag.String("boundsnames", "_%sMin,_%sMax,%sCount", "comma-separated `formats` of the names of the -bounds constants, with %s standing for the type")
+	goString     = flag.Bool("gostring", false, "generate a GoString method returning the qualified names of the constants")
+	description  = flag.Bool("description", false, "generate a Description method returning the doc comments of the constants")
+	isDeprecated = flag.Bool("isdeprecated", false, "generate an IsDeprecated method")
+	acceptDepr   = flag.Bool("acceptdeprecated", false, "accept the names of deprecated constants when unmarshaling but never marshal them")
+	isValid      = flag.Bool("isvalid", false, "generate an IsValid method")
+	jsonSchema   = flag.Bool("jsonschema", false, "write a JSON Schema file for each type")
+	proto        = flag.Bool("proto", false, "write a protobuf enum definition for each type")
+	protoZero    = flag.String("protozero", "", "`name` of a value numbered 0 added ahead of the constants with -proto when none of them is")
+	protoPrefix  = flag.Bool("protoprefix", true, "prefix the protobuf value names with the name of the type")
+	typeScript   = flag.Bool("typescript", false, "write a TypeScript file declaring a union type for each type")
+	openAPI      = flag.String("openapi", "", "OpenAPI `file` whose schemas named after the types get their enum lists updated")
+	check        = flag.Bool("check", false, "fail if the generated files are not up to date instead of writing them")
+	showDiff     = flag.Bool("diff", false, "print a unified diff of the generated files against those on disk instead of writing them")
+	toStdout     = flag.Bool("stdout", false, "write the generated files to standard output instead of to disk")
+	watchFlag    = flag.Bool("watch", false, "regenerate the files each time the Go files of the packages change, until interrupted")
+	openAPICheck = flag.Bool("openapicheck", false, "fail instead of updating the -openapi file if its enum lists are out of date")
+	tests        = flag.Bool("tests", false, "write a _test.go file checking that every constant round-trips through JSON")
+	benchmarks   = flag.Bool("benchmarks", false, "write a _test.go file holding benchmarks of MarshalJSON and UnmarshalJSON")
+	fuzz         = flag.Bool("fuzz", false, "write a _test.go file holding a fuzz target for UnmarshalJSON")
+	parse        = flag.Bool("parse", false, "generate ParseT and MustParseT functions")
+	switchMethod = flag.Bool("switch", false, "generate a Switch method taking a function for each constant")
+	registry     = flag.Bool("registry", false, "register the types with the github.com/davars/jsonenums/enumreg package")
+	cycle        = flag.Bool("cycle", false, "generate Next and Prev methods going through the constants in the order set by -order")
+	displayNames = flag.String("displaynames", "", "`directory` of <lang>.json files of display names generating DisplayName methods")
+	random       = flag.Bool("random", false, "generate RandomT functions picking a value at random")
+	quick        = flag.Bool("quick", false, "generate Generate methods for testing/quick")
+	slices       = flag.Bool("slices", false, "generate ParseTSlice and TSliceStrings functions")
+	flagValue    = flag.Bool("flag", false, "generate Set and String methods for flag.Value")
+	cli          = flag.Bool("cli", false, "generate TFlag types, and the -flag methods, for github.com/urfave/cli/v2")
+	kong         = flag.Bool("kong", false, "generate Decode methods for github.com/alecthomas/kong; cannot be used with -env")
+	cobra        = flag.Bool("cobra", false, "generate TCompletion functions completing the names for github.com/spf13/cobra")
+	pflag        = flag.Bool("pflag", false, "generate Type methods and TFlagHelp functions, and the -flag methods, for github.com/spf13/pflag")
+	pgx          = flag.Bool("pgx", false, "generate TextValue and ScanText methods and a RegisterTPgx function for github.com/jackc/pgx/v5")
+	gorm         = flag.Bool("gorm", false, "generate a GormDataType method, and the -sql methods, for gorm.io/gorm models")
+	ent          = flag.Bool("ent", false, "generate Values and Validate methods, and the -sql methods, for entgo.io/ent enum fields")
+	sql          = flag.Bool("sql", false, "generate Value and Scan methods for database/sql")
+	gobMethods   = flag.Bool("gob", false, "generate GobEncode and GobDecode methods and a RegisterTGob function")
+	binary       = flag.String("binary", "", "generate MarshalBinary and UnmarshalBinary methods encoding values as their names or integers; one of name, int")
+	xmlMethods   = flag.Bool("xml", false, "generate MarshalXML, UnmarshalXML, MarshalXMLAttr and UnmarshalXMLAttr methods")
+	gql          = flag.Bool("gql", false, "generate MarshalGQL and UnmarshalGQL methods for github.com/99designs/gqlgen")
+	appendFuncs  = flag.Bool("append", false, "generate AppendJSON and AppendText methods appending the values to a buffer")
+	jsonV2       = flag.Bool("jsonv2", false, "generate MarshalJSONTo and UnmarshalJSONFrom methods for encoding/json/v2")
+	easyJSON     = flag.Bool("easyjson", false, "generate MarshalEasyJSON and UnmarshalEasyJSON methods for github.com/mailru/easyjson")
+	jsoniter     = flag.Bool("jsoniter", false, "generate RegisterTJsoniter functions for github.com/json-iterator/go")
+	cbor         = flag.Bool("cbor", false, "generate MarshalCBOR and UnmarshalCBOR methods for github.com/fxamacker/cbor/v2")
+	bsonMethods  = flag.Bool("bson", false, "generate MarshalBSONValue and UnmarshalBSONValue methods for go.mongodb.org/mongo-driver/v2")
+	msgPack      = flag.Bool("msgpack", false, "generate EncodeMsgpack and DecodeMsgpack methods for github.com/vmihailenco/msgpack/v5")
+	bind         = flag.Bool("bind", false, "generate UnmarshalParam methods, and the -text methods, for binding request parameters with echo and gin")
+	env          = flag.Bool("env", false, "generate Decode methods, and the -text methods, for decoding environment variables with envconfig and env")
+	mapKeys      = flag.Bool("mapkeys", false, "generate MarshalText and UnmarshalText methods so maps keyed by the types use the names as JSON keys")
+	toml         = flag.Bool("toml", false, "generate MarshalText and UnmarshalText methods for TOML and, with -tests, tests using both major TOML libraries")
+	yamlMethods  = flag.Bool("yaml", false, "generate MarshalYAML and UnmarshalYAML methods for gopkg.in/yaml.v3")
 )
 
 func main() {
 	flag.Parse()
-	if len(*typeNames) == 0 {
-		log.Fatalf("the flag -type must be set")
+
+	// Each argument is a package or pattern, followed by an equal sign and the
+	// types to generate for in its packages if they are not those of -type.
+	// The default is the package in the current directory.
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	targets := make([]target, len(args))
+	configDir := "."
+	for i, arg := range args {
+		pattern := arg
+		if j := strings.LastIndex(arg, "="); j >= 0 {
+			pattern = arg[:j]
+			targets[i].types = strings.Split(arg[j+1:], ",")
+		}
+		if fi, err := os.Stat(pattern); err == nil && fi.IsDir() {
+			// A directory, even when not written as a relative path.
+			abs, err := filepath.Abs(pattern)
+			if err != nil {
+				log.Fatalf("unable to determine absolute filepath for requested path %s: %v",
+					pattern, err)
+			}
+			pattern = abs
+			if len(args) == 1 {
+				configDir = abs
+			}
+		}
+		targets[i].pattern = pattern
+	}
+	extra, err := loadConfig(configDir)
+	if err != nil {
+		log.Fatalf("reading configuration: %v", err)
+	}
+	if *headerFile != "" {
+		text, err := ioutil.ReadFile(*headerFile)
+		if err != nil {
+			log.Fatalf("reading header: %v", err)
+		}
+		header = commentLines(text)
+	}
+	var displayNamesByLang map[string]map[string]map[string]string
+	if *displayNames != "" {
+		displayNamesByLang, err = loadDisplayNames(*displayNames)
+		if err != nil {
+			log.Fatalf("reading display names: %v", err)
+		}
+	}
+
+	// Without -type, the types are discovered from their doc comments.
+	var types []string
+	if len(*typeNames) > 0 {
+		types = strings.Split(*typeNames, ",")
+	}
+	// listed tells which targets were given their own types.
+	listed := make([]bool, len(targets))
+	patterns := make([]string, len(targets))
+	for i := range targets {
+		listed[i] = targets[i].types != nil
+		if !listed[i] {
+			targets[i].types = types
+		}
+		targets[i].given = listed[i] || commandLineFlags["type"]
+		patterns[i] = targets[i].pattern
+	}
+
+	cfg := parser.Config{Tests: *includeTests}
+	if len(*buildTags) > 0 {
+		cfg.Tags = strings.Split(*buildTags, ",")
+	}
+	if *watchFlag {
+		watch(cfg, patterns)
+	}
+
+	// The patterns are parsed, and then their packages generated for, each
+	// in its own goroutine, so that regenerating a whole monorepo takes a
+	// single run.
+	pkgs := make([][]*parser.Package, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, pattern string) {
+			defer wg.Done()
+			var err error
+			if pkgs[i], err = cfg.ParsePackages(pattern); err != nil {
+				log.Fatalf("parsing package: %v", err)
+			}
+		}(i, t.pattern)
+	}
+	wg.Wait()
+	// matched holds the index of the target matching each package, by
+	// directory, so that no package is generated twice at the same time.
+	matched := make(map[string]int)
+	for i, t := range targets {
+		for _, pkg := range pkgs[i] {
+			if j, ok := matched[pkg.Dir]; ok && j != i {
+				log.Fatalf("package %s is matched by both %s and %s", pkg.Path, targets[j].pattern, t.pattern)
+			}
+			matched[pkg.Dir] = i
+		}
+	}
+	if len(matched) > 1 && *output != "" {
+		log.Fatalf("the flag -output cannot be used with %d packages", len(matched))
+	}
+	if *split && (*output != "" || *filename != "") {
+		log.Fatalf("the flags -output and -filename cannot be used with -split")
+	}
+	if *output != "" && *filename != "" {
+		log.Fatalf("the flags -output and -filename cannot be used together")
+	}
+	if *filename != "" && (filepath.Base(*filename) != *filename || !strings.HasSuffix(*filename, ".go")) {
+		log.Fatalf("the flag -filename must name a .go file, without a directory")
 	}
-	types := strings.Split(*typeNames, ",")
 
-	// Only one directory at a time can be processed, and the default is ".".
-	dir := "."
-	if args := flag.Args(); len(args) == 1 {
-		dir = args[0]
-	} else if len(args) > 1 {
-		log.Fatalf("only one directory at a time")
+	opts := generator.Options{
+		Command:          commandLine(),
+		TrimPrefix:       *trimPrefix,
+		Transform:        *transform,
+		LineComment:      *lineComment,
+		Char:             *char,
+		Unknown:          *unknown,
+		Defaults:         strings.Split(*defaults, ","),
+		Null:             *null,
+		Text:             *text,
+		JSONv2:           *jsonV2,
+		Append:           *appendFuncs,
+		Jsoniter:         *jsoniter,
+		EasyJSON:         *easyJSON,
+		YAML:             *yamlMethods,
+		TOML:             *toml,
+		MapKeys:          *mapKeys,
+		Bind:             *bind,
+		Env:              *env,
+		XML:              *xmlMethods,
+		GQL:              *gql,
+		MsgPack:          *msgPack,
+		CBOR:             *cbor,
+		BSON:             *bsonMethods,
+		SQL:              *sql,
+		Ent:              *ent,
+		Gorm:             *gorm,
+		Pgx:              *pgx,
+		Binary:           *binary,
+		Gob:              *gobMethods,
+		Flag:             *flagValue,
+		PFlag:            *pflag,
+		Cobra:            *cobra,
+		CLI:              *cli,
+		Kong:             *kong,
+		Values:           *valuesFuncs,
+		All:              *all,
+		IsValid:          *isValid,
+		GoString:         *goString,
+		EnumSet:          *enumSet,
+		Description:      *description,
+		IsDeprecated:     *isDeprecated,
+		AcceptDeprecated: *acceptDepr,
+		Parse:            *parse,
+		Slices:           *slices,
+		Random:           *random,
+		Switch:           *switchMethod,
+		Cycle:            *cycle,
+		Registry:         *registry,
+		DisplayNames:     displayNamesByLang,
+		Quick:            *quick,
+		Tests:            *tests,
+		Fuzz:             *fuzz,
+		Benchmarks:       *benchmarks,
+		BitFlags:         *bitFlags,
+		AcceptNumbers:    *numbers,
+		Marshal:          *marshal,
+		Receiver:         *receiver,
+		Lookup:           *lookup,
+		Order:            *order,
+	}
+	if *bounds {
+		opts.Bounds = strings.Split(*boundsNames, ",")
+	}
+	if *templateFile != "" {
+		text, err := ioutil.ReadFile(*templateFile)
+		if err != nil {
+			log.Fatalf("reading template: %v", err)
+		}
+		opts.Template, err = generator.ParseTemplate(filepath.Base(*templateFile), string(text), nil)
+		if err != nil {
+			log.Fatalf("parsing template: %v", err)
+		}
+	}
+
+	// A package must define all the types it is generated for if it is the
+	// only one, or the only one of a target given its own types.
+	pkgEnums := make([][][]generator.Enum, len(targets))
+	for i, t := range targets {
+		pkgEnums[i] = make([][]generator.Enum, len(pkgs[i]))
+		single := len(matched) == 1 || listed[i] && len(pkgs[i]) == 1
+		for j, pkg := range pkgs[i] {
+			wg.Add(1)
+			go func(i, j int, pkg *parser.Package, t target) {
+				defer wg.Done()
+				pkgEnums[i][j] = generatePackage(pkg, t, single, opts, extra)
+			}(i, j, pkg, t)
+		}
+	}
+	wg.Wait()
+	var enums []generator.Enum
+	for i, t := range targets {
+		var targetEnums []generator.Enum
+		for _, e := range pkgEnums[i] {
+			targetEnums = append(targetEnums, e...)
+		}
+		if len(targetEnums) == 0 && t.types == nil {
+			log.Fatalf("no type in %s is marked with %s; set the flag -type or mark the types",
+				t.pattern, parser.GenerateDirective)
+		}
+		if len(targetEnums) == 0 {
+			log.Fatalf("no package matching %s defines any of the types %s", t.pattern, strings.Join(t.types, ","))
+		}
+		enums = append(enums, targetEnums...)
+	}
+
+	if *openAPI != "" {
+		err := updateOpenAPI(*openAPI, enums, *openAPICheck || *check)
+		if err != nil && *check {
+			stale = append(stale, err.Error())
+		} else if err != nil {
+			log.Fatalf("updating OpenAPI document: %s", err)
+		}
+	}
+
+	if *toStdout {
+		if err := writeStdout(); err != nil {
+			log.Fatalf("writing to standard output: %s", err)
+		}
+	}
+
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		for _, s := range stale {
+			log.Print(s)
+		}
+		log.Fatalf("generated files are out of date; run jsonenums without -check")
+	}
+}
+
+// A target is a package or pattern given as an argument, with the types to
+// generate for in the packages it matches, or nil to discover them.
+type target struct {
+	pattern string
+	types   []string
+	// given is set if the types were given on the command line, so that
+	// those listed by the configuration of a package do not replace them.
+	given bool
+}
+
+// generatePackage writes the files requested for pkg, one of the packages
+// matched by t, and returns the enums of its types. If single, it must define
+// all the types of t, while otherwise only the types it defines are generated
+// for.
+func generatePackage(pkg *parser.Package, t target, single bool, opts generator.Options, extra []extraTemplate) []generator.Enum {
+	settings := packageSettings{
+		opts:   opts,
+		layout: layout{prefix: *outputPrefix, suffix: *outputSuffix, filename: *filename, split: *split},
+	}
+	if pkg.Dir != runConfigDir {
+		var err error
+		settings, err = packageConfig(pkg.Dir, opts, settings.layout)
+		if err != nil {
+			log.Fatalf("reading configuration: %v", err)
+		}
+	}
+	opts, l := settings.opts, settings.layout
+	if *output != "" && (l.split || l.filename != "") {
+		log.Fatalf("the flag -output cannot be used with the flags split or filename of the configuration of package %s", pkg.Name)
+	}
+	explicit := t.types != nil
+	if settings.types != nil && !t.given {
+		// The types listed by the configuration of the package must all be
+		// defined by it.
+		t.types, single, explicit = settings.types, true, false
+	}
+	extra = append(extra[:len(extra):len(extra)], settings.extra...)
+
+	var pkgTypes []string
+	switch {
+	case t.types == nil:
+		pkgTypes = pkg.MarkedTypes()
+	case single:
+		pkgTypes = t.types
+	default:
+		for _, typeName := range t.types {
+			if pkg.HasType(typeName) {
+				pkgTypes = append(pkgTypes, typeName)
+			}
+		}
+	}
+	if len(pkgTypes) == 0 {
+		return nil
+	}
+	// groups holds the types generated to each file.
+	var groups [][]string
+	if l.split {
+		for _, typeName := range pkgTypes {
+			groups = append(groups, []string{typeName})
+		}
+	} else {
+		if l.filename != "" {
+			// The file does not depend on the order of -type or of the
+			// declarations.
+			sort.Strings(pkgTypes)
+		}
+		// Types given the same options in their line comment, if any, are
+		// generated together, to a file named after the first.
+		index := make(map[string]int)
+		for _, typeName := range pkgTypes {
+			options := pkg.TypeOptions(typeName)
+			i, ok := index[options]
+			if !ok {
+				i = len(groups)
+				index[options] = i
+				groups = append(groups, nil)
+			}
+			groups[i] = append(groups[i], typeName)
+		}
+		if len(groups) > 1 && (*output != "" || l.filename != "") {
+			log.Fatalf("the types of package %s have different options in their comments and cannot be generated to a single file with -output or -filename", pkg.Name)
+		}
+	}
+	// With -gogenerate, a single directive regenerating all the types of the
+	// package is added to the first of its files in sorted order.
+	var directiveFile, directive string
+	if *goGenerate {
+		for _, group := range groups {
+			if path := outputFile(pkg, group, l); directiveFile == "" || path < directiveFile {
+				directiveFile = path
+			}
+		}
+		directive = canonicalArgs(pkg, pkgTypes, explicit, settings.set)
+	}
+	var enums []generator.Enum
+	for _, group := range groups {
+		opts := canonicalOptions(withTypeOptions(opts, pkg, group[0]), pkg, group, explicit, settings.set)
+		if outputFile(pkg, group, l) == directiveFile {
+			opts.GoGenerate = directive
+		}
+		enums = append(enums, generate(pkg, group, opts, l)...)
+	}
+	for _, e := range extra {
+		opts := canonicalOptions(opts, pkg, pkgTypes, explicit, settings.set)
+		opts.Template = e.tmpl
+		src, err := generator.Generate(pkg, pkgTypes, opts)
+		if err := writeSource(filepath.Join(pkg.Dir, e.output), src, err); err != nil {
+			log.Fatalf("writing output of template %s: %s", e.tmpl.Name(), err)
+		}
 	}
-	dir, err := filepath.Abs(dir)
+	return enums
+}
+
+// A layout holds the settings naming the files generated for a package.
+type layout struct {
+	prefix, suffix, filename string
+	split                    bool
+}
+
+// outputFile returns the path of the file generated for the given types of
+// pkg, named as set by l unless -output is given.
+func outputFile(pkg *parser.Package, types []string, l layout) string {
+	if *output != "" {
+		return *output
+	}
+	if l.filename != "" {
+		return filepath.Join(pkg.Dir, l.filename)
+	}
+	name := l.prefix + strings.ToLower(types[0]) + strings.TrimSuffix(l.suffix, ".go")
+	if *includeTests {
+		name += "_test"
+	}
+	return filepath.Join(pkg.Dir, name+".go")
+}
+
+// generate writes the files requested for the given types of pkg, named as
+// set by l, and returns their enums.
+func generate(pkg *parser.Package, types []string, opts generator.Options, l layout) []generator.Enum {
+	enums, err := generator.Enums(pkg, types, opts)
 	if err != nil {
-		log.Fatalf("unable to determine absolute filepath for requested path %s: %v",
-			dir, err)
+		log.Fatalf("%v", err)
+	}
+
+	outputPath := outputFile(pkg, types, l)
+
+	// Generate a single file holding the methods for all the types.
+	src, err := generator.Generate(pkg, types, opts)
+	if err := writeSource(outputPath, src, err); err != nil {
+		log.Fatalf("writing output: %s", err)
+	}
+
+	// The tests are named after the output file without the _test suffix
+	// added by -includetests, which it may have.
+	base := strings.TrimSuffix(strings.TrimSuffix(outputPath, ".go"), "_test")
+	if opts.Tests || opts.Fuzz || opts.Benchmarks {
+		testPath := base + "_test.go"
+		if testPath == outputPath {
+			testPath = base + "_tests_test.go"
+		}
+		src, err := generator.GenerateTests(pkg, types, opts)
+		if err := writeSource(testPath, src, err); err != nil {
+			log.Fatalf("writing tests: %s", err)
+		}
+	}
+	if opts.Tests && opts.TOML {
+		testPath := base + "_toml_test.go"
+		src, err := generator.GenerateTOMLTests(pkg, types, opts)
+		if err := writeSource(testPath, src, err); err != nil {
+			log.Fatalf("writing TOML tests: %s", err)
+		}
+	}
+
+	if *jsonSchema {
+		for _, e := range enums {
+			if err := writeJSONSchema(filepath.Dir(outputPath), e, opts); err != nil {
+				log.Fatalf("writing JSON Schema: %s", err)
+			}
+		}
+	}
+
+	if *proto {
+		for _, e := range enums {
+			if err := writeProto(filepath.Dir(outputPath), opts.Command, e, *protoZero, *protoPrefix); err != nil {
+				log.Fatalf("writing protobuf definition: %s", err)
+			}
+		}
 	}
 
-	pkg, err := parser.ParsePackage(dir)
+	if *typeScript {
+		for _, e := range enums {
+			if err := writeTypeScript(filepath.Dir(outputPath), opts.Command, e); err != nil {
+				log.Fatalf("writing TypeScript: %s", err)
+			}
+		}
+	}
+	return enums
+}
+
+// commandLine returns the arguments jsonenums was run with, as recorded in the
+// generated files. The -check, -diff and -stdout flags are left out so that
+// comparing an up to date file does not report its header as different.
+func commandLine() string {
+	var args []string
+	for _, arg := range os.Args[1:] {
+		switch strings.TrimLeft(arg, "-") {
+		case "check", "check=true", "check=false", "diff", "diff=true", "diff=false",
+			"stdout", "stdout=true", "stdout=false":
+			continue
+		}
+		args = append(args, arg)
+	}
+	return strings.Join(args, " ")
+}
+
+// withTypeOptions returns opts with the options given in the line comment of
+// the named type applied.
+func withTypeOptions(opts generator.Options, pkg *parser.Package, typeName string) generator.Options {
+	options := pkg.TypeOptions(typeName)
+	if options == "" {
+		return opts
+	}
+	opts, err := typeOptions(opts, options)
 	if err != nil {
-		log.Fatalf("parsing package: %v", err)
+		log.Fatalf("options of type %s: %v", typeName, err)
 	}
+	return opts
+}
 
-	var analysis = struct {
-		Command        string
-		PackageName    string
-		TypesAndValues map[string][]string
-	}{
-		Command:        strings.Join(os.Args[1:], " "),
-		PackageName:    pkg.Name,
-		TypesAndValues: make(map[string][]string),
+// canonicalOptions returns opts with the canonical arguments of the file
+// generated for the given types of pkg set as its command with -reproducible.
+// The flags in configured, set by the configuration file of pkg, are left out.
+func canonicalOptions(opts generator.Options, pkg *parser.Package, types []string, explicit bool, configured map[string]bool) generator.Options {
+	args := canonicalArgs(pkg, types, explicit, configured)
+	if *reproducible {
+		opts.Command = args
 	}
+	return opts
+}
 
-	// Run generate for each type.
-	for _, typeName := range types {
-		values, err := pkg.ValuesOfType(typeName)
-		if err != nil {
-			log.Fatalf("finding values for type %v: %v", typeName, err)
+// canonicalArgs returns the arguments of jsonenums run in the directory of pkg
+// to regenerate the given types as they are now, which do not depend on how
+// it was first run: the flags set, sorted by name, other than those only
+// affecting this run and those in configured, which the configuration file
+// read from that directory sets, with -type listing the types if they were
+// given explicitly and paths relative to that directory.
+func canonicalArgs(pkg *parser.Package, types []string, explicit bool, configured map[string]bool) string {
+	var args []string
+	if explicit {
+		args = append(args, "-type="+strings.Join(types, ","))
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if configured[f.Name] {
+			return
+		}
+		value := f.Value.String()
+		switch f.Name {
+		case "type", "check", "diff", "stdout", "watch":
+			return
+		case "output", "header", "template", "openapi", "displaynames":
+			if abs, err := filepath.Abs(value); err == nil {
+				if rel, err := filepath.Rel(pkg.Dir, abs); err == nil {
+					value = rel
+				}
+			}
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() && value == "true" {
+			args = append(args, "-"+f.Name)
+			return
+		}
+		arg := "-" + f.Name + "=" + value
+		if strings.ContainsAny(arg, " \t\"") {
+			arg = strconv.Quote(arg)
+		}
+		args = append(args, arg)
+	})
+	return strings.Join(args, " ")
+}
+
+// outputMu guards stale, stdoutFiles and the differences printed with -diff,
+// since packages are generated concurrently.
+var outputMu sync.Mutex
+
+// stale holds a description of every file found out of date with -check.
+var stale []string
+
+// stdoutFiles holds the files written to standard output with -stdout, once
+// all of them are generated.
+var stdoutFiles []stdoutFile
+
+// A stdoutFile is a file generated with -stdout.
+type stdoutFile struct {
+	path string
+	data []byte
+}
+
+// writeStdout writes the files generated with -stdout to standard output:
+// a single one as it is, several in the txtar format, each preceded by a
+// "-- path --" line giving its path relative to the current directory.
+func writeStdout() error {
+	// The files of each package are kept in the order they were generated,
+	// and the packages, generated concurrently, sorted by directory.
+	sort.SliceStable(stdoutFiles, func(i, j int) bool {
+		return filepath.Dir(stdoutFiles[i].path) < filepath.Dir(stdoutFiles[j].path)
+	})
+	if len(stdoutFiles) == 1 {
+		_, err := os.Stdout.Write(stdoutFiles[0].data)
+		return err
+	}
+	var buf bytes.Buffer
+	for _, f := range stdoutFiles {
+		path := f.path
+		if wd, err := os.Getwd(); err == nil && filepath.IsAbs(path) {
+			if rel, err := filepath.Rel(wd, path); err == nil {
+				path = rel
+			}
 		}
-		analysis.TypesAndValues[typeName] = values
+		fmt.Fprintf(&buf, "-- %s --\n", path)
+		buf.Write(f.data)
+	}
+	_, err := buf.WriteTo(os.Stdout)
+	return err
+}
+
+// writeFile writes data to the file at path or, with -check, records in stale
+// how the contents of the file differ from data. With -diff, the differences
+// are printed instead of writing the file, and with -stdout the contents.
+func writeFile(path string, data []byte) error {
+	if *toStdout || *check || *showDiff {
+		outputMu.Lock()
+		defer outputMu.Unlock()
+	}
+	if *toStdout {
+		stdoutFiles = append(stdoutFiles, stdoutFile{path, data})
+		return nil
+	}
+	if !*check && !*showDiff {
+		return ioutil.WriteFile(path, data, 0644)
+	}
+	old, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if *showDiff {
+		// A missing file is shown as empty.
+		fmt.Print(unifiedDiff(path, old, data))
+	}
+	if !*check {
+		return nil
+	}
+	if os.IsNotExist(err) {
+		stale = append(stale, fmt.Sprintf("%s: missing", path))
+		return nil
+	}
+	if diff := firstDiff(old, data); diff != "" {
+		stale = append(stale, fmt.Sprintf("%s: %s", path, diff))
+	}
+	return nil
+}
 
-		var buf bytes.Buffer
-		if err := generatedTmpl.Execute(&buf, analysis); err != nil {
-			log.Fatalf("generating code: %v", err)
+// firstDiff describes the first line in which have and want differ, or
+// returns the empty string if they are equal.
+func firstDiff(have, want []byte) string {
+	if bytes.Equal(have, want) {
+		return ""
+	}
+	haveLines := strings.Split(string(have), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; ; i++ {
+		switch {
+		case i >= len(haveLines):
+			return fmt.Sprintf("line %d: missing, want %q", i+1, wantLines[i])
+		case i >= len(wantLines):
+			return fmt.Sprintf("line %d: have %q, want end of file", i+1, haveLines[i])
+		case haveLines[i] != wantLines[i]:
+			return fmt.Sprintf("line %d: have %q, want %q", i+1, haveLines[i], wantLines[i])
 		}
+	}
+}
+
+// writeSource writes the generated Go source to path. If the source could not
+// be formatted it is written as is, so the user can compile it to see the error.
+func writeSource(path string, src []byte, err error) error {
+	if err != nil && src == nil {
+		return err
+	}
+	if err != nil {
+		// Should never happen, but can arise when developing this code.
+		log.Printf("warning: internal error: %s", err)
+		log.Printf("warning: compile the package to analyze the error")
+	}
+	return writeFile(path, withHeader(src))
+}
+
+// header holds the comments read from the -header file.
+var header []byte
 
-		src, err := format.Source(buf.Bytes())
+// loadDisplayNames reads the display names in the <lang>.json files of dir,
+// by language, type and constant.
+func loadDisplayNames(dir string) (map[string]map[string]map[string]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .json files in %s", dir)
+	}
+	byLang := make(map[string]map[string]map[string]string)
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
 		if err != nil {
-			// Should never happen, but can arise when developing this code.
-			// The user can compile the output to see the error.
-			log.Printf("warning: internal error: invalid Go generated: %s", err)
-			log.Printf("warning: compile the package to analyze the error")
-			src = buf.Bytes()
+			return nil, err
+		}
+		var names map[string]map[string]string
+		if err := json.Unmarshal(data, &names); err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", path, err)
+		}
+		byLang[strings.TrimSuffix(filepath.Base(path), ".json")] = names
+	}
+	return byLang, nil
+}
+
+// commentLines returns text with each line turned into a // comment, unless
+// it already is one.
+func commentLines(text []byte) []byte {
+	var buf bytes.Buffer
+	for _, line := range strings.Split(strings.TrimRight(string(text), "\n"), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		switch {
+		case strings.HasPrefix(line, "//"):
+		case line == "":
+			line = "//"
+		default:
+			line = "// " + line
 		}
+		buf.WriteString(line + "\n")
+	}
+	return buf.Bytes()
+}
+
+// withHeader returns data preceded by the -header comments, if any, and a
+// blank line. It is used for the generated files in which // starts a comment.
+func withHeader(data []byte) []byte {
+	if header == nil {
+		return data
+	}
+	return append(append(append([]byte(nil), header...), '\n'), data...)
+}
 
-		output := strings.ToLower(*outputPrefix + typeName +
-			*outputSuffix + ".go")
-		outputPath := filepath.Join(dir, output)
-		if err := ioutil.WriteFile(outputPath, src, 0644); err != nil {
-			log.Fatalf("writing output: %s", err)
+// A schemaFragment is a JSON Schema fragment describing the JSON form of a type.
+type schemaFragment struct {
+	Type  string          `json:"type"`
+	Items *schemaFragment `json:"items,omitempty"`
+	Enum  []interface{}   `json:"enum,omitempty"`
+}
+
+// writeJSONSchema writes a JSON Schema describing the values marshaled for e
+// as set by opts to the file t.schema.json in dir, where t is the lower-cased
+// name of the type: the names accepted for e, their lists with -bitflags, or
+// the values of its constants with -marshal=number.
+func writeJSONSchema(dir string, e generator.Enum, opts generator.Options) error {
+	schema := &schemaFragment{Type: "string"}
+	for _, name := range e.AcceptedNames() {
+		schema.Enum = append(schema.Enum, name)
+	}
+	switch {
+	case opts.BitFlags:
+		schema = &schemaFragment{Type: "array", Items: schema}
+	case opts.Marshal == "number":
+		schema = &schemaFragment{Type: "integer"}
+		if e.IsFloat {
+			schema.Type = "number"
+		}
+		for _, v := range e.CanonicalValues() {
+			// Floats may be exact fractions, as in 5/2.
+			r, ok := new(big.Rat).SetString(v.ExactValue())
+			if !ok {
+				return fmt.Errorf("invalid value %s of constant %s", v.ExactValue(), v.OriginalName)
+			}
+			n := r.RatString()
+			if !r.IsInt() {
+				f, _ := r.Float64()
+				n = strconv.FormatFloat(f, 'g', -1, 64)
+			}
+			schema.Enum = append(schema.Enum, json.Number(n))
+		}
+	}
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := strings.ToLower(e.Name) + ".schema.json"
+	return writeFile(filepath.Join(dir, name), append(b, '\n'))
+}
+
+// writeTypeScript writes a TypeScript declaration of a union type holding the
+// names accepted for e to the file t.ts in dir, where t is the lower-cased
+// name of the type.
+func writeTypeScript(dir, command string, e generator.Enum) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// generated by jsonenums %s; DO NOT EDIT\n\n", command)
+	fmt.Fprintf(&buf, "export type %s =", e.Name)
+	for _, name := range e.AcceptedNames() {
+		// JSON string literals are valid TypeScript string literals.
+		lit, err := json.Marshal(name)
+		if err != nil {
+			return err
 		}
+		fmt.Fprintf(&buf, "\n  | %s", lit)
 	}
+	buf.WriteString(";\n")
+	name := strings.ToLower(e.Name) + ".ts"
+	return writeFile(filepath.Join(dir, name), withHeader(buf.Bytes()))
 }
@@ -0,0 +1,181 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/davars/jsonenums/generator"
+	"gopkg.in/yaml.v3"
+)
+
+// updateOpenAPI rewrites the enum lists of the schemas named after the given
+// types in the OpenAPI document at path, which may be written in YAML or JSON.
+// If check is set the document is left untouched and an error is returned if
+// any of the lists does not match the names UnmarshalJSON accepts.
+func updateOpenAPI(path string, enums []generator.Enum, check bool) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s: %v", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return fmt.Errorf("%s is empty", path)
+	}
+
+	var drifted []string
+	for _, e := range enums {
+		schema := findSchema(doc.Content[0], e.Name)
+		if schema == nil {
+			return fmt.Errorf("no schema named %s in %s", e.Name, path)
+		}
+		names := e.AcceptedNames()
+		list := mappingValue(schema, "enum")
+		if list != nil && sameScalars(list, names) {
+			continue
+		}
+		if check {
+			drifted = append(drifted, e.Name)
+			continue
+		}
+		if list == nil {
+			list = &yaml.Node{}
+			schema.Content = append(schema.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "enum"}, list)
+		}
+		*list = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: list.Style}
+		for _, name := range names {
+			list.Content = append(list.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name})
+		}
+	}
+	if check {
+		if len(drifted) > 0 {
+			return fmt.Errorf("enum of %s in %s does not match the constants",
+				strings.Join(drifted, ", "), path)
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		var compact bytes.Buffer
+		if err := writeJSONNode(&compact, doc.Content[0]); err != nil {
+			return err
+		}
+		if err := json.Indent(&buf, compact.Bytes(), "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+	} else {
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return err
+		}
+		enc.Close()
+	}
+	return writeFile(path, buf.Bytes())
+}
+
+// findSchema returns the schema with the given name, looking for it in the
+// components of an OpenAPI 3 document and in the definitions of a Swagger 2 one.
+func findSchema(root *yaml.Node, name string) *yaml.Node {
+	if schemas := mappingValue(mappingValue(root, "components"), "schemas"); schemas != nil {
+		if schema := mappingValue(schemas, name); schema != nil {
+			return schema
+		}
+	}
+	return mappingValue(mappingValue(root, "definitions"), name)
+}
+
+// mappingValue returns the value of the given key in a mapping node, or nil if
+// node is not a mapping or does not contain the key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// sameScalars reports whether node is a sequence holding exactly the given values.
+func sameScalars(node *yaml.Node, values []string) bool {
+	if node.Kind != yaml.SequenceNode || len(node.Content) != len(values) {
+		return false
+	}
+	for i, n := range node.Content {
+		if n.Kind != yaml.ScalarNode || n.Value != values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeJSONNode writes node as compact JSON, keeping the order of the keys of
+// every mapping.
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return writeJSONNode(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(node.Content[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, n := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, n); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" {
+			s, _ := json.Marshal(node.Value)
+			buf.Write(s)
+		} else {
+			// Numbers, booleans and null are written as they appear.
+			buf.WriteString(node.Value)
+		}
+	default:
+		return fmt.Errorf("unexpected YAML node at line %d", node.Line)
+	}
+	return nil
+}
@@ -12,7 +12,7 @@
 // limitations under the License.
 
 // Package parser parses Go code and keeps track of all the types defined
-// and provides access to all the constants defined for an int type.
+// and provides access to all the constants defined for an int or string type.
 package parser
 
 import (
@@ -20,8 +20,15 @@ import (
 	"fmt"
 	"go/ast"
 	"go/constant"
+	goparser "go/parser"
 	"go/token"
 	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
@@ -29,66 +36,355 @@ import (
 // A Package contains all the information related to a parsed package.
 type Package struct {
 	Name string
+	Path string       // The import path of the package.
+	Dir  string       // The directory holding the files of the package.
 	buf  bytes.Buffer // Accumulated output.
 
 	defs  map[*ast.Ident]types.Object
+	scope *types.Scope
+	fset  *token.FileSet
 	files []*goFile
 }
 
-// ParsePackage parses the package in the given directory and returns it.
-func ParsePackage(directory string) (*Package, error) {
-	p := &Package{}
+// A Config controls how packages are parsed. The zero value parses the files
+// built by default for the current platform.
+type Config struct {
+	Tags  []string // Build tags that select the files to parse.
+	Tests bool     // Whether to parse the _test.go files of the package too.
+}
 
-	cfg := &packages.Config{
-		Mode: packages.LoadSyntax,
-		// TODO: Need to think about constants in test files. Maybe write type_string_test.go
-		// in a separate pass? For later.
-		Tests: false,
-	}
+// ParsePackage parses the package named by pattern and returns it. The pattern
+// is either an absolute directory, a relative one starting with . or .., or an
+// import path resolved from the current directory.
+func ParsePackage(pattern string) (*Package, error) {
+	return Config{}.ParsePackage(pattern)
+}
 
-	pkgs, err := packages.Load(cfg, directory)
+// ParsePackage parses the package named by pattern as configured by c and
+// returns it.
+func (c Config) ParsePackage(pattern string) (*Package, error) {
+	pkgs, err := c.ParsePackages(pattern)
 	if err != nil {
 		return nil, err
 	}
 	if len(pkgs) != 1 {
 		return nil, fmt.Errorf("%d packages found", len(pkgs))
 	}
+	return pkgs[0], nil
+}
+
+// ParsePackages parses the packages matched by pattern, which may also end in
+// /... to match every package in a directory tree, as configured by c. If the
+// tree is the root of a workspace, holding a go.work file but no module, the
+// pattern matches the packages of every module the workspace uses.
+func (c Config) ParsePackages(pattern string) ([]*Package, error) {
+	pkgs, err := c.load(packages.LoadSyntax, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if c.Tests {
+		pkgs = testVariants(pkgs)
+	}
+
+	var ps []*Package
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) == 0 {
+			// Other errors are tolerated, since the package may not compile
+			// until the generated file is updated.
+			if len(pkg.Errors) > 0 {
+				return nil, pkg.Errors[0]
+			}
+			return nil, fmt.Errorf("no Go files in package %s", pkg.PkgPath)
+		}
+		p := &Package{
+			Name:  pkg.Name,
+			Path:  pkg.PkgPath,
+			Dir:   filepath.Dir(pkg.GoFiles[0]),
+			defs:  pkg.TypesInfo.Defs,
+			scope: pkg.Types.Scope(),
+			fset:  pkg.Fset,
+			files: make([]*goFile, len(pkg.Syntax)),
+		}
+		for i, file := range pkg.Syntax {
+			p.files[i] = &goFile{
+				file: file,
+				pkg:  p,
+			}
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// PackageDirs returns the directories of the packages matched by pattern, as
+// in ParsePackages, without parsing their files, so that it succeeds even if
+// they do not compile.
+func (c Config) PackageDirs(pattern string) ([]string, error) {
+	pkgs, err := c.load(packages.LoadFiles, pattern)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) > 0 {
+			dirs = append(dirs, filepath.Dir(pkg.GoFiles[0]))
+		}
+	}
+	return dirs, nil
+}
+
+// load loads the packages matched by pattern with the given mode.
+func (c Config) load(mode packages.LoadMode, pattern string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode:  mode,
+		Tests: c.Tests,
+	}
+	if len(c.Tags) > 0 {
+		cfg.BuildFlags = []string{fmt.Sprintf("-tags=%s", strings.Join(c.Tags, " "))}
+	}
+
+	patterns := []string{pattern}
+	if root, modules, err := workspace(pattern); err != nil {
+		return nil, err
+	} else if modules != nil {
+		// The go command does not match directories outside of modules, so
+		// each module is matched on its own, from the root of the workspace.
+		cfg.Dir = root
+		patterns = patterns[:0]
+		for _, m := range modules {
+			if !filepath.IsAbs(m) {
+				m = filepath.Join(root, m)
+			}
+			patterns = append(patterns, m+"/...")
+		}
+	}
+
+	return packages.Load(cfg, patterns...)
+}
+
+// workspace returns, if pattern matches the tree of a directory holding a
+// go.work file but no go.mod file, the absolute path of that directory and the
+// directories of the modules listed by its use directives.
+func workspace(pattern string) (string, []string, error) {
+	dir := strings.TrimSuffix(pattern, "/...")
+	if dir == pattern || !filepath.IsAbs(dir) && !strings.HasPrefix(dir, ".") {
+		// Not a tree, or an import path.
+		return "", nil, nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		return "", nil, nil
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "go.work"))
+	if os.IsNotExist(err) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Directives are either on a single line, as in "use ./a", or grouped
+	// in a block, as in "use (" followed by a directory on each line.
+	var modules []string
+	block := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case block && fields[0] == ")":
+			block = false
+		case block:
+			modules = append(modules, strings.Trim(fields[0], `"`))
+		case fields[0] == "use" && len(fields) > 1 && fields[1] == "(":
+			block = true
+		case fields[0] == "use" && len(fields) > 1:
+			modules = append(modules, strings.Trim(fields[1], `"`))
+		}
+	}
+	if len(modules) == 0 {
+		return "", nil, fmt.Errorf("no modules used by %s", filepath.Join(dir, "go.work"))
+	}
+	return dir, modules, nil
+}
 
-	pkg := pkgs[0]
-	p.Name = pkg.Name
-	p.defs = pkg.TypesInfo.Defs
-	p.files = make([]*goFile, len(pkg.Syntax))
+// testVariants returns, out of the packages loaded with tests, the variant of
+// every package compiled for testing that includes the _test.go files
+// declaring the same package, or the package itself if there are no such
+// files. External test packages and generated test mains are dropped.
+func testVariants(pkgs []*packages.Package) []*packages.Package {
+	test := make(map[string]*packages.Package)
+	for _, pkg := range pkgs {
+		if pkg.ID == fmt.Sprintf("%s [%s.test]", pkg.PkgPath, pkg.PkgPath) {
+			test[pkg.PkgPath] = pkg
+		}
+	}
+	var variants []*packages.Package
+	for _, pkg := range pkgs {
+		if pkg.ID != pkg.PkgPath {
+			continue
+		}
+		if t, ok := test[pkg.PkgPath]; ok {
+			pkg = t
+		}
+		variants = append(variants, pkg)
+	}
+	return variants
+}
 
-	for i, file := range pkg.Syntax {
+// NewPackage returns the package made of the given files, already parsed and
+// type-checked as pkg, with info holding at least their definitions. It lets
+// tools such as analyzers run by go vet, which are handed the files, use the
+// package without loading it again.
+func NewPackage(fset *token.FileSet, files []*ast.File, pkg *types.Package, info *types.Info) *Package {
+	p := &Package{
+		Name:  pkg.Name(),
+		Path:  pkg.Path(),
+		defs:  info.Defs,
+		scope: pkg.Scope(),
+		fset:  fset,
+		files: make([]*goFile, len(files)),
+	}
+	if len(files) > 0 {
+		p.Dir = filepath.Dir(fset.Position(files[0].Pos()).Filename)
+	}
+	for i, file := range files {
 		p.files[i] = &goFile{
 			file: file,
 			pkg:  p,
 		}
 	}
+	return p
+}
 
-	return p, nil
+// HasType reports whether the package declares a type with the given name.
+func (pkg *Package) HasType(typeName string) bool {
+	_, ok := pkg.scope.Lookup(typeName).(*types.TypeName)
+	return ok
 }
 
-// generate produces the String method for the named type.
-func (pkg *Package) ValuesOfType(typeName string) (_ []string, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err = r.(error)
-		}
-	}()
-	var values []string
+// A Value is a constant defined for a type.
+type Value struct {
+	Name  string         // The name of the constant.
+	Value constant.Value // The value of the constant, an integer, a float or a string.
+	// The text of the comment on the same line as the constant, if it is
+	// a single line comment; empty otherwise.
+	LineComment string
+	// Whether the constant is marked with the SkipDirective, or with a
+	// json:"-" line comment.
+	Skip bool
+	// The name given by a line comment in the syntax of struct tags, as in
+	// json:"in_progress", if any.
+	JSONName string
+	// The text of the doc comment of the constant, without directives.
+	Doc string
+	// Whether a paragraph of the doc comment starts with "Deprecated: ".
+	Deprecated bool
+	// Whether the constant is marked with the CanonicalDirective.
+	Canonical bool
+	// The names listed by the AcceptDirectives of the constant.
+	Accept []string
+	// The protobuf number given by the ProtoDirective of the constant, in
+	// decimal, if any.
+	Proto string
+	// The position of the name of the constant in its declaration.
+	Pos token.Position
+	// Whether the constant has an unsigned integer type, whose values may
+	// not fit in an int64.
+	Unsigned bool
+	// Whether the value of the constant is written as a rune literal, as in
+	// Spades Suit = '♠', possibly converted to its type.
+	Char bool
+}
+
+// ValuesOfType returns the names of the constants defined for the named type.
+func (pkg *Package) ValuesOfType(typeName string) ([]string, error) {
+	values, err := pkg.Values(typeName)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = v.Name
+	}
+	return names, nil
+}
+
+// An Error is a problem found at a position of the source of a package.
+type Error struct {
+	Pos token.Position
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// An ErrorList holds all the problems found while looking for the constants of
+// a type, so that they can be fixed at once.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, e := range l {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Values returns the constants defined for the named type. If some of them
+// cannot be handled, the returned error is an ErrorList describing each.
+func (pkg *Package) Values(typeName string) ([]Value, error) {
+	obj, ok := pkg.scope.Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("no values defined for type %s", typeName)
+	}
+	if basic, ok := obj.Type().Underlying().(*types.Basic); !ok || basic.Info()&(types.IsInteger|types.IsFloat|types.IsString) == 0 {
+		return nil, ErrorList{{
+			Pos: pkg.fset.Position(obj.Pos()),
+			Msg: fmt.Sprintf("type %s has underlying type %s; only integer, float and string types are supported",
+				typeName, obj.Type().Underlying()),
+		}}
+	}
+	var values []Value
+	var errs ErrorList
 	for _, file := range pkg.files {
 		// Set the state for this run of the walker.
 		file.typeName = typeName
+		file.typ = obj.Type()
 		file.values = nil
+		file.errs = nil
 		if file.file != nil {
 			ast.Inspect(file.file, file.genDecl)
+			errs = append(errs, file.errs...)
 			for _, v := range file.values {
-				values = append(values, v.originalName)
+				values = append(values, Value{
+					Name:        v.originalName,
+					Value:       v.constant,
+					LineComment: v.lineComment,
+					Skip:        v.skip,
+					JSONName:    v.jsonName,
+					Doc:         v.doc,
+					Deprecated:  isDeprecated(v.doc),
+					Canonical:   v.canonical,
+					Accept:      v.accept,
+					Proto:       v.proto,
+					Pos:         v.pos,
+					Unsigned:    v.constant.Kind() == constant.Int && !v.signed,
+					Char:        v.char,
+				})
 			}
 		}
 	}
 
+	if len(errs) > 0 {
+		return nil, errs
+	}
 	if len(values) == 0 {
 		return nil, fmt.Errorf("no values defined for type %s", typeName)
 	}
@@ -96,19 +392,280 @@ func (pkg *Package) ValuesOfType(typeName string) (_ []string, err error) {
 	return values, nil
 }
 
+// HasMethod reports whether a method with the given name is declared for the
+// named type, or for the type it is an alias of. Files generated by jsonenums
+// are ignored, so that regenerating them does not depend on their previous
+// contents.
+func (pkg *Package) HasMethod(typeName, method string) bool {
+	for _, file := range pkg.files {
+		if file.file == nil || isGenerated(file.file) {
+			continue
+		}
+		for _, decl := range file.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Name.Name != method {
+				continue
+			}
+			typ := fn.Recv.List[0].Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			if id, ok := typ.(*ast.Ident); ok && pkg.sameType(id.Name, typeName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameType reports whether the two names denote the same type, possibly
+// through aliases.
+func (pkg *Package) sameType(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ta, ok := pkg.scope.Lookup(a).(*types.TypeName)
+	if !ok {
+		return false
+	}
+	tb, ok := pkg.scope.Lookup(b).(*types.TypeName)
+	return ok && types.Identical(ta.Type(), tb.Type())
+}
+
+// GenerateDirective marks the types returned by MarkedTypes when it appears
+// on a line of its own in their doc comment.
+const GenerateDirective = "//jsonenums:generate"
+
+// SkipDirective marks constants that are left out of the values of their type
+// when it appears on a line of their doc comment or as their line comment.
+const SkipDirective = "//jsonenums:skip"
+
+// CanonicalDirective marks, out of several constants holding the same value,
+// the one whose name is used when marshaling the value. It appears on a line
+// of their doc comment or as their line comment.
+const CanonicalDirective = "//jsonenums:canonical"
+
+// AcceptDirective, followed by a comma-separated list of Go string literals,
+// gives additional names that are accepted for a constant when unmarshaling,
+// as in
+//
+//	//jsonenums:accept "in-progress", "IN_PROGRESS"
+//
+// It appears on a line of its doc comment or as its line comment.
+const AcceptDirective = "//jsonenums:accept"
+
+// ProtoDirective, followed by a number, gives the number of a constant in the
+// protobuf enum definitions written by jsonenums, as in
+//
+//	Pending Status = "pending" //jsonenums:proto 1
+//
+// It appears on a line of its doc comment or as its line comment.
+const ProtoDirective = "//jsonenums:proto"
+
+// OptionsDirective, followed by a space and comma-separated options, gives
+// options of a type that apply to it alone when it is the line comment of its
+// declaration, as in
+//
+//	type Status int //jsonenums: transform=snake, trimprefix=Status, sql
+//
+// It also marks the type as the GenerateDirective does.
+const OptionsDirective = "//jsonenums:"
+
+// MarkedTypes returns the names of the types whose doc comment holds the
+// GenerateDirective, or whose line comment is the OptionsDirective, in
+// declaration order.
+func (pkg *Package) MarkedTypes() []string {
+	var names []string
+	for _, file := range pkg.files {
+		if file.file == nil {
+			continue
+		}
+		for _, decl := range file.file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				tspec := spec.(*ast.TypeSpec) // Guaranteed to succeed as this is TYPE.
+				doc := tspec.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					// The comment is attached to the declaration in "type T int".
+					doc = decl.Doc
+				}
+				if hasDirective(doc, GenerateDirective) || hasDirective(tspec.Comment, OptionsDirective) {
+					names = append(names, tspec.Name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// TypeOptions returns the options given for the named type by the
+// OptionsDirective, or the empty string if there is none.
+func (pkg *Package) TypeOptions(typeName string) string {
+	for _, file := range pkg.files {
+		if file.file == nil {
+			continue
+		}
+		for _, decl := range file.file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				tspec := spec.(*ast.TypeSpec) // Guaranteed to succeed as this is TYPE.
+				if tspec.Name.Name == typeName {
+					return strings.Join(directiveArgs(tspec.Comment, OptionsDirective), ", ")
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// GeneratedTypes returns the names of the types with methods declared in the
+// files generated by jsonenums, in the order of the first of them.
+func (pkg *Package) GeneratedTypes() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, file := range pkg.files {
+		if file.file == nil || !isGenerated(file.file) {
+			continue
+		}
+		for _, decl := range file.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 {
+				continue
+			}
+			typ := fn.Recv.List[0].Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			if id, ok := typ.(*ast.Ident); ok && !seen[id.Name] {
+				seen[id.Name] = true
+				names = append(names, id.Name)
+			}
+		}
+	}
+	return names
+}
+
+// hasDirective reports whether one of the lines of the comment is the given
+// directive, possibly followed by a space and other text.
+func hasDirective(doc *ast.CommentGroup, directive string) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if c.Text == directive || strings.HasPrefix(c.Text, directive+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// directiveArgs returns the text following each occurrence of the directive
+// on a line of the comment.
+func directiveArgs(doc *ast.CommentGroup, directive string) []string {
+	if doc == nil {
+		return nil
+	}
+	var args []string
+	for _, c := range doc.List {
+		if strings.HasPrefix(c.Text, directive+" ") {
+			args = append(args, strings.TrimSpace(c.Text[len(directive):]))
+		}
+	}
+	return args
+}
+
+// parseStrings parses a comma-separated list of Go string literals.
+func parseStrings(list string) ([]string, error) {
+	expr, err := goparser.ParseExpr("[]string{" + list + "}")
+	if err != nil {
+		return nil, fmt.Errorf("invalid list of strings %s", list)
+	}
+	var strs []string
+	for _, elt := range expr.(*ast.CompositeLit).Elts {
+		lit, ok := elt.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, fmt.Errorf("invalid list of strings %s", list)
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+// jsonTag returns the name given by the json key of a comment in the syntax of
+// struct tags, as in json:"in_progress,omitempty", without its options.
+func jsonTag(comment string) string {
+	name, _ := reflect.StructTag(comment).Lookup("json")
+	if i := strings.Index(name, ","); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// isRuneLiteral reports whether expr is a rune literal, possibly parenthesized
+// or converted to a type.
+func isRuneLiteral(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return e.Kind == token.CHAR
+	case *ast.ParenExpr:
+		return isRuneLiteral(e.X)
+	case *ast.CallExpr:
+		return len(e.Args) == 1 && isRuneLiteral(e.Args[0])
+	}
+	return false
+}
+
+// isDeprecated reports whether a paragraph of the doc comment text starts
+// with "Deprecated: ", following the Go convention.
+func isDeprecated(doc string) bool {
+	for _, p := range strings.Split(doc, "\n\n") {
+		if strings.HasPrefix(p, "Deprecated: ") {
+			return true
+		}
+	}
+	return false
+}
+
+// isGenerated reports whether the file was generated by jsonenums, possibly
+// with a header preceding the line saying so.
+func isGenerated(file *ast.File) bool {
+	for _, c := range file.Comments {
+		if c.Pos() > file.Package {
+			break
+		}
+		if strings.HasPrefix(c.Text(), "generated by jsonenums") {
+			return true
+		}
+	}
+	return false
+}
+
 // This parser is based on https://raw.githubusercontent.com/golang/tools/63e6ed9258fa6cbc90aab9b1eef3e0866e89b874/cmd/stringer/stringer.go
 
 // constantValue represents a declared constant.
 type constantValue struct {
-	originalName string // The name of the constant.
-	// The value is stored as a bit pattern alone. The boolean tells us
-	// whether to interpret it as an int64 or a uint64; the only place
-	// this matters is when sorting.
-	// Much of the time the str field is all we need; it is printed
-	// by constantValue.String.
-	value  uint64 // Will be converted to int64 when needed.
-	signed bool   // Whether the constant is a signed type.
-	str    string // The string representation given by the "go/constant" package.
+	originalName string         // The name of the constant.
+	signed       bool           // Whether the constant is of a signed integer type.
+	constant     constant.Value // The value of the constant.
+	lineComment  string         // The text of a single line comment following the constant.
+	skip         bool           // Whether the constant is marked with the SkipDirective.
+	jsonName     string         // The name given by a json struct tag as line comment.
+	doc          string         // The text of the doc comment of the constant.
+	canonical    bool           // Whether the constant is marked with the CanonicalDirective.
+	accept       []string       // The names listed by the AcceptDirectives of the constant.
+	proto        string         // The number given by the ProtoDirective of the constant.
+	pos          token.Position // The position of the name of the constant.
+	char         bool           // Whether the value is written as a rune literal.
 }
 
 // goFile holds a single parsed file and associated data.
@@ -117,7 +674,9 @@ type goFile struct {
 	file *ast.File // Parsed AST.
 	// These fields are reset for each type being generated.
 	typeName string          // Name of the constant type.
+	typ      types.Type      // The constant type, with aliases resolved.
 	values   []constantValue // Accumulator for constant values of that type.
+	errs     ErrorList       // Accumulator for the constants that cannot be handled.
 }
 
 // genDecl processes one declaration clause.
@@ -127,83 +686,100 @@ func (f *goFile) genDecl(node ast.Node) bool {
 		// We only care about const declarations.
 		return true
 	}
-	// The name of the type of the constants we are declaring.
-	// Can change if this is a multi-element declaration.
-	typ := ""
 	// Loop over the elements of the declaration. Each element is a ValueSpec:
 	// a list of names possibly followed by a type, possibly followed by values.
-	// If the type and value are both missing, we carry down the type (and value,
-	// but the "go/types" package takes care of that).
+	// The type checker tells the type of each constant, whether it is given
+	// explicitly, carried down from a previous element, obtained from
+	// another constant or a conversion, or named through an alias.
 	for _, spec := range decl.Specs {
 		vspec := spec.(*ast.ValueSpec) // Guaranteed to succeed as this is CONST.
-		if vspec.Type == nil && len(vspec.Values) > 0 {
-			// "X = 1". With no type but a value. If the constant is untyped,
-			// skip this vspec and reset the remembered type.
-			typ = ""
-
-			// If this is a simple type conversion, remember the type.
-			// We don't mind if this is actually a call; a qualified call won't
-			// be matched (that will be SelectorExpr, not Ident), and only unusual
-			// situations will result in a function call that appears to be
-			// a type conversion.
-			ce, ok := vspec.Values[0].(*ast.CallExpr)
-			if !ok {
-				continue
-			}
-			id, ok := ce.Fun.(*ast.Ident)
-			if !ok {
-				continue
-			}
-			typ = id.Name
-		}
-		if vspec.Type != nil {
-			// "X T". We have a type. Remember it.
-			ident, ok := vspec.Type.(*ast.Ident)
-			if !ok {
-				continue
-			}
-			typ = ident.Name
-		}
-		if typ != f.typeName {
-			// This is not the type we're looking for.
-			continue
-		}
 		// We now have a list of names (from one line of source code) all being
 		// declared with the desired type.
 		// Grab their names and actual values and store them in f.values.
-		for _, name := range vspec.Names {
+		for i, name := range vspec.Names {
 			if name.Name == "_" {
 				continue
 			}
 			// This dance lets the type checker find the values for us. It's a
 			// bit tricky: look up the object declared by the name, find its
 			// types.Const, and extract its value.
+			pos := f.pkg.fset.Position(name.Pos())
 			obj, ok := f.pkg.defs[name]
-			if !ok {
-				panic(fmt.Errorf("no value for constant %s", name))
-			}
-			info := obj.Type().Underlying().(*types.Basic).Info()
-			if info&types.IsInteger == 0 {
-				panic(fmt.Errorf("can't handle non-integer constant type %s", typ))
+			if !ok || obj == nil {
+				if id, ok := vspec.Type.(*ast.Ident); ok && id.Name == f.typeName {
+					f.errs = append(f.errs, &Error{Pos: pos, Msg: fmt.Sprintf("no value for constant %s; does the package type-check?", name)})
+				}
+				continue
 			}
-			value := obj.(*types.Const).Val() // Guaranteed to succeed as this is CONST.
-			if value.Kind() != constant.Int {
-				panic(fmt.Errorf("can't happen: constant is not an integer %s", name))
+			if !types.Identical(obj.Type(), f.typ) {
+				// This is not the type we're looking for.
+				continue
 			}
-			i64, isInt := constant.Int64Val(value)
-			u64, isUint := constant.Uint64Val(value)
-			if !isInt && !isUint {
-				panic(fmt.Errorf("internal error: value of %s is not an integer: %s", name, value.String()))
+			basic, ok := obj.Type().Underlying().(*types.Basic)
+			if !ok || basic.Info()&(types.IsInteger|types.IsFloat|types.IsString) == 0 {
+				f.errs = append(f.errs, &Error{Pos: pos, Msg: fmt.Sprintf("constant %s has underlying type %s; only integer, float and string types are supported", name, obj.Type().Underlying())})
+				continue
 			}
-			if !isInt {
-				u64 = uint64(i64)
+			info := basic.Info()
+			value := obj.(*types.Const).Val() // Guaranteed to succeed as this is CONST.
+			if info&types.IsFloat != 0 {
+				// Whole numbers are represented as integers by go/constant.
+				value = constant.ToFloat(value)
 			}
 			v := constantValue{
 				originalName: name.Name,
-				value:        u64,
-				signed:       info&types.IsUnsigned == 0,
-				str:          value.String(),
+				constant:     value,
+				pos:          pos,
+			}
+			switch value.Kind() {
+			case constant.Int:
+				_, isInt := constant.Int64Val(value)
+				_, isUint := constant.Uint64Val(value)
+				if !isInt && !isUint {
+					f.errs = append(f.errs, &Error{Pos: pos, Msg: fmt.Sprintf("value %s of constant %s does not fit in 64 bits", value, name)})
+					continue
+				}
+				v.signed = info&types.IsUnsigned == 0
+			case constant.Float, constant.String:
+			default:
+				f.errs = append(f.errs, &Error{Pos: pos, Msg: fmt.Sprintf("value %s of constant %s is not an integer, a float or a string", value, name)})
+				continue
+			}
+			if i < len(vspec.Values) {
+				v.char = isRuneLiteral(vspec.Values[i])
+			}
+			if c := vspec.Comment; c != nil && len(c.List) == 1 {
+				v.lineComment = strings.TrimSpace(c.Text())
+				v.jsonName = jsonTag(v.lineComment)
+			}
+			doc := vspec.Doc
+			if doc == nil && len(decl.Specs) == 1 {
+				// The comment is attached to the declaration in "const C T = 1".
+				doc = decl.Doc
+			}
+			v.skip = hasDirective(doc, SkipDirective) || hasDirective(vspec.Comment, SkipDirective) || v.jsonName == "-"
+			v.canonical = hasDirective(doc, CanonicalDirective) || hasDirective(vspec.Comment, CanonicalDirective)
+			for _, list := range append(directiveArgs(doc, AcceptDirective), directiveArgs(vspec.Comment, AcceptDirective)...) {
+				names, err := parseStrings(list)
+				if err != nil {
+					f.errs = append(f.errs, &Error{Pos: pos, Msg: fmt.Sprintf("%s of constant %s: %v", AcceptDirective, name, err)})
+					continue
+				}
+				v.accept = append(v.accept, names...)
+			}
+			for _, number := range append(directiveArgs(doc, ProtoDirective), directiveArgs(vspec.Comment, ProtoDirective)...) {
+				n, err := strconv.ParseInt(number, 10, 32)
+				if err != nil {
+					f.errs = append(f.errs, &Error{Pos: pos, Msg: fmt.Sprintf("%s of constant %s: invalid number %s", ProtoDirective, name, number)})
+					continue
+				}
+				if v.proto != "" && v.proto != strconv.FormatInt(n, 10) {
+					f.errs = append(f.errs, &Error{Pos: pos, Msg: fmt.Sprintf("constant %s has several %s numbers", name, ProtoDirective)})
+					continue
+				}
+				v.proto = strconv.FormatInt(n, 10)
 			}
+			v.doc = strings.TrimSpace(doc.Text())
 			f.values = append(f.values, v)
 		}
 	}
@@ -0,0 +1,129 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/davars/jsonenums/generator"
+	"github.com/davars/jsonenums/parser"
+)
+
+var protoTmpl = template.Must(template.New("proto").Parse(`// generated by jsonenums {{.Command}}; DO NOT EDIT
+
+syntax = "proto3";
+
+enum {{.Name}} {
+{{- if .AllowAlias}}
+  option allow_alias = true;
+{{- end}}
+{{- range .Values}}
+  {{.Name}} = {{.Number}};
+{{- end}}
+}
+`))
+
+// A protoValue is a value of a protobuf enum.
+type protoValue struct {
+	Name   string
+	Number int
+}
+
+// writeProto writes a protobuf enum definition holding the constants of e to
+// the file t.proto in dir, where t is the lower-cased name of the type.
+//
+// The values are named in screaming snake case, prefixed with the name of the
+// type if prefix is set. Each distinct value is numbered as given by the
+// parser.ProtoDirective of one of its constants or, for integer types, by its
+// Go value, so that the numbers do not change as constants are added or
+// reordered, and aliases share the number of the constant they alias. The
+// values are listed by number, then in declaration order. As proto3 requires
+// the first value to be numbered 0, a value named zero is added with number 0
+// if no constant has it and zero is not empty.
+func writeProto(dir, command string, e generator.Enum, zero string, prefix bool) error {
+	screaming := generator.ScreamingSnake
+	typePrefix := screaming(e.Name) + "_"
+	name := func(s string) string {
+		s = screaming(s)
+		if prefix && !strings.HasPrefix(s, typePrefix) {
+			s = typePrefix + s
+		}
+		return s
+	}
+
+	// numbers holds the number of each distinct value, by ExactValue, and
+	// given the ExactValue numbered by each number.
+	numbers := make(map[string]string)
+	given := make(map[string]string)
+	for _, v := range e.DeclaredValues() {
+		n := v.Proto
+		if n == "" {
+			continue
+		}
+		if m, ok := numbers[v.ExactValue()]; ok && m != n {
+			return fmt.Errorf("constants of %s holding %s have different numbers %s and %s", e.Name, v.ExactValue(), m, n)
+		}
+		numbers[v.ExactValue()] = n
+	}
+	var values []protoValue
+	aliases := false
+	hasZero := false
+	for _, v := range e.DeclaredValues() {
+		n, ok := numbers[v.ExactValue()]
+		if !ok {
+			if e.IsString || e.IsFloat {
+				return fmt.Errorf("constant %s of %s has no protobuf number; give one with %s", v.OriginalName, e.Name, parser.ProtoDirective)
+			}
+			if _, err := strconv.ParseInt(v.ExactValue(), 10, 32); err != nil {
+				return fmt.Errorf("value %s of constant %s of %s is not a protobuf number; give one with %s", v.ExactValue(), v.OriginalName, e.Name, parser.ProtoDirective)
+			}
+			n = v.ExactValue()
+			numbers[v.ExactValue()] = n
+		}
+		if other, ok := given[n]; ok && other != v.ExactValue() {
+			return fmt.Errorf("constant %s of %s has number %s, already given to another value", v.OriginalName, e.Name, n)
+		}
+		aliases = aliases || given[n] != ""
+		given[n] = v.ExactValue()
+		hasZero = hasZero || n == "0"
+		number, _ := strconv.Atoi(n)
+		values = append(values, protoValue{name(v.OriginalName), number})
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Number < values[j].Number })
+	if !hasZero {
+		if zero == "" {
+			return fmt.Errorf("no constant of %s has protobuf number 0, required by proto3; name a value to add with -protozero", e.Name)
+		}
+		values = append([]protoValue{{name(zero), 0}}, values...)
+	}
+
+	var buf bytes.Buffer
+	err := protoTmpl.Execute(&buf, struct {
+		Command    string
+		Name       string
+		AllowAlias bool
+		Values     []protoValue
+	}{command, e.Name, aliases, values})
+	if err != nil {
+		return err
+	}
+	file := strings.ToLower(e.Name) + ".proto"
+	return writeFile(filepath.Join(dir, file), withHeader(buf.Bytes()))
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 Google Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to writing, software distributed
+// under the License is distributed on a "AS IS" BASIS, WITHOUT WARRANTIES OR
+// CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/davars/jsonenums/parser"
+)
+
+// watchInterval is how often the Go files are checked for changes with -watch.
+const watchInterval = 500 * time.Millisecond
+
+// watch runs jsonenums again with the same arguments but -watch, first right
+// away and then each time a Go file in the directories of the packages matched
+// by the patterns changes. It never returns.
+//
+// Running jsonenums in a separate process keeps the errors of a run, such as
+// those of a package being edited that does not compile yet, from stopping
+// the watch.
+func watch(cfg parser.Config, patterns []string) {
+	exe, err := os.Executable()
+	if err != nil {
+		log.Fatalf("finding jsonenums: %v", err)
+	}
+	var args []string
+	for _, arg := range os.Args[1:] {
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+		if strings.HasPrefix(arg, "-") && name == "watch" {
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	var last map[string]time.Time
+	var lastErr string
+	for {
+		var dirs []string
+		var err error
+		for _, pattern := range patterns {
+			var d []string
+			if d, err = cfg.PackageDirs(pattern); err != nil {
+				break
+			}
+			dirs = append(dirs, d...)
+		}
+		if err != nil && err.Error() != lastErr {
+			log.Printf("finding packages: %v", err)
+		}
+		lastErr = ""
+		if err != nil {
+			lastErr = err.Error()
+		}
+		// The files are listed after each run, so the files it generates do
+		// not trigger another.
+		files := goFiles(dirs)
+		if !sameFiles(files, last) {
+			if last != nil {
+				log.Printf("regenerating")
+			}
+			cmd := exec.Command(exe, args...)
+			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+			if err := cmd.Run(); err != nil {
+				log.Printf("jsonenums failed: %v", err)
+			}
+			files = goFiles(dirs)
+		}
+		last = files
+		time.Sleep(watchInterval)
+	}
+}
+
+// goFiles returns the modification times of the Go files in dirs, by path.
+func goFiles(dirs []string) map[string]time.Time {
+	files := make(map[string]time.Time)
+	for _, dir := range dirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+		for _, path := range paths {
+			if fi, err := os.Stat(path); err == nil {
+				files[path] = fi.ModTime()
+			}
+		}
+	}
+	return files
+}
+
+// sameFiles reports whether a and b hold the same files with the same
+// modification times.
+func sameFiles(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, t := range a {
+		if u, ok := b[path]; !ok || !u.Equal(t) {
+			return false
+		}
+	}
+	return true
+}